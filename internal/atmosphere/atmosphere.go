@@ -1,20 +1,60 @@
 package atmosphere
 
 import (
+	"sync"
+
+	"github.com/g3n/engine/math32"
+
 	"cbeimers113/strands/internal/chem"
-	"cbeimers113/strands/internal/context"
+	"cbeimers113/strands/internal/config"
 	"cbeimers113/strands/internal/state"
 )
 
+var (
+	// How much colder the air gets with each metre of altitude, in °C, so that air carried upwards can saturate and rain
+	LapseRate float32 = 0.25
+
+	// The temperature in °C of the air at sea level, which the atmosphere starts out at and which blows in from beyond the map
+	SeaAirTemperature float32 = 22
+)
+
+// A pair of buffers to hold the current and next values of a field while it's updated
+type scratch struct {
+	values []float32
+	next   []float32
+}
+
 type Atmosphere struct {
-	*context.Context
+	Cfg *config.Config
 
 	cells [][][]*state.Cell
+
+	// Pointers into each cell's values, indexed the same way as GetCells, so that updates can skip map lookups
+	quantities   map[chem.ElementType][]*float32
+	temperatures []*float32
+	winds        []*math32.Vector3
+
+	// The wind across the faces of each cell in the positive x, y and z directions, indexed the same way as GetCells
+	windX []float32
+	windY []float32
+	windZ []float32
+
+	// The wind blowing in over the edges of the map into the first cells along the x and z axes,
+	// indexed by y + z*height and x + y*width
+	edgeX []float32
+	edgeZ []float32
+
+	// The height of the lowest cell above the ground in each column, indexed by x + z*width
+	ground []int
+
+	// Scratch buffers for moving the values of each field around, reused between ticks to avoid reallocating them
+	scratch []scratch
+	warmth  []float32 // The temperature of each cell at the start of the tick, for working out the wind
 }
 
 // Create the atmosphere
-func New(ctx *context.Context) *Atmosphere {
-	a := &Atmosphere{Context: ctx}
+func New(cfg *config.Config) *Atmosphere {
+	a := &Atmosphere{Cfg: cfg, ground: make([]int, cfg.Simulation.Width*cfg.Simulation.Depth)}
 	a.cells = make([][][]*state.Cell, a.Cfg.Simulation.Width)
 
 	for x := 0; x < a.Cfg.Simulation.Width; x++ {
@@ -24,26 +64,26 @@ func New(ctx *context.Context) *Atmosphere {
 			a.cells[x][y] = make([]*state.Cell, a.Cfg.Simulation.Depth)
 
 			for z := 0; z < a.Cfg.Simulation.Depth; z++ {
-				var t float32 = 22.0
 				a.cells[x][y][z] = &state.Cell{
 					Quantities:  make(map[chem.ElementType]*chem.Quantity),
-					Temperature: t,
+					Temperature: seaAirTemperature(y),
 				}
 
-				// Starting quantities
-				a.cells[x][y][z].Quantities[chem.Water] = &chem.Quantity{
-					Value: 0.5,
-					Units: chem.Litre,
-				}
+				// Starting quantities, the air starts out as sea air
+				fill(a.cells[x][y][z])
+				a.cells[x][y][z].Quantities[chem.Water].Value = seaAir(chem.Water, y)
 			}
 		}
 	}
 
+	a.index()
+
 	return a
 }
 
-func Load(ctx *context.Context, cells []*state.Cell) *Atmosphere {
-	a := &Atmosphere{Context: ctx}
+// Load the atmosphere from a linear slice of cells
+func Load(cfg *config.Config, cells []*state.Cell) *Atmosphere {
+	a := &Atmosphere{Cfg: cfg, ground: make([]int, cfg.Simulation.Width*cfg.Simulation.Depth)}
 	a.cells = make([][][]*state.Cell, a.Cfg.Simulation.Width)
 
 	w := a.Cfg.Simulation.Width
@@ -56,24 +96,189 @@ func Load(ctx *context.Context, cells []*state.Cell) *Atmosphere {
 			a.cells[x][y] = make([]*state.Cell, a.Cfg.Simulation.Depth)
 			for z := 0; z < d; z++ {
 				a.cells[x][y][z] = cells[x+w*(y+h*z)]
+				fill(a.cells[x][y][z])
 			}
 		}
 	}
 
+	a.index()
+
 	return a
 }
 
-// Update the atmosphere
-func (a *Atmosphere) Update(deltaTime float32) {
-	for x := 0; x < a.Cfg.Simulation.Width; x++ {
-		for y := 0; y < a.Cfg.Simulation.Height; y++ {
-			for z := 0; z < a.Cfg.Simulation.Depth; z++ {
-				// TODO: this
-			}
+// Give a cell the starting amount of every element it doesn't have yet, so that saves from before an element was added still load
+func fill(cell *state.Cell) {
+	if cell.Quantities == nil {
+		cell.Quantities = make(map[chem.ElementType]*chem.Quantity)
+	}
+
+	for _, element := range chem.Elements {
+		if _, ok := cell.Quantities[element.Type]; !ok {
+			cell.Quantities[element.Type] = &chem.Quantity{Value: element.Starting, Units: element.Units}
+		}
+	}
+}
+
+// Get the starting amount of an element in a cubic metre of the sea air at height y, which is half saturated with water vapour
+func seaAir(element chem.ElementType, y int) float32 {
+	if element == chem.Water {
+		return chem.SaturationHumidity(seaAirTemperature(y)) / 2
+	}
+
+	for _, e := range chem.Elements {
+		if e.Type == element {
+			return e.Starting
+		}
+	}
+
+	return 0
+}
+
+// Get the temperature of the sea air at height y
+func seaAirTemperature(y int) float32 {
+	return SeaAirTemperature - LapseRate*float32(y)
+}
+
+// Update the atmosphere, deltaTime is the amount of simulated time in ms. The wind is worked out once, then the air is
+// carried along it in as many short steps as it takes for the wind not to move more out of a cell than is stable,
+// and diffused once over the whole tick. Returns the net amount of each element that the wind blew in over the edges of the map.
+func (a *Atmosphere) Update(deltaTime float32) map[chem.ElementType]float32 {
+	w := a.Cfg.Simulation.Width
+	h := a.Cfg.Simulation.Height
+	d := a.Cfg.Simulation.Depth
+	seconds := deltaTime / 1000
+	fastest := a.updateWind()
+
+	steps := max(1, int(math32.Ceil(fastest/maxCourant*seconds)))
+	step := seconds / float32(steps)
+
+	// Each element and the temperature move independently of each other, so they're carried along the wind
+	// and then diffused at the same time, each with its own scratch buffers
+	fields := make([][]*float32, 0, len(chem.ElementTypes)+1)
+	diffusions := make([]diffusion, 0, len(chem.ElementTypes)+1)
+	elements := newDiffusion(w, h, d, ElementDiffusivity*seconds)
+	outside := make([][]float32, 0, len(chem.ElementTypes)+1)
+	for _, element := range chem.ElementTypes {
+		fields = append(fields, a.quantities[element])
+		diffusions = append(diffusions, elements)
+		outside = append(outside, make([]float32, h))
+
+		for y := range outside[len(outside)-1] {
+			outside[len(outside)-1][y] = seaAir(element, y)
+		}
+	}
+
+	fields = append(fields, a.temperatures)
+	diffusions = append(diffusions, newDiffusion(w, h, d, HeatDiffusivity*seconds))
+	outside = append(outside, make([]float32, h))
+	for y := range outside[len(outside)-1] {
+		outside[len(outside)-1][y] = seaAirTemperature(y)
+	}
+
+	if len(a.scratch) != len(fields) {
+		a.scratch = make([]scratch, len(fields))
+	}
+
+	blown := make([]float64, len(fields))
+
+	var wg sync.WaitGroup
+	for f, field := range fields {
+		wg.Add(1)
+		go func(f int, field []*float32) {
+			defer wg.Done()
+			a.scratch[f].step(field, func(values, next []float32) {
+				for i := 0; i < steps; i++ {
+					blown[f] += a.advect(values, next, outside[f], step)
+					copy(values, next)
+				}
+
+				diffusions[f].apply(values, next)
+			})
+		}(f, field)
+	}
+
+	wg.Wait()
+
+	exchanged := make(map[chem.ElementType]float32, len(chem.ElementTypes))
+	for f, element := range chem.ElementTypes {
+		exchanged[element] = float32(blown[f])
+	}
+
+	return exchanged
+}
+
+// Gather a per-cell value into a flat buffer, let step compute the next values from it, then scatter them back.
+// Field holds a pointer to the value in each cell.
+func (s *scratch) step(field []*float32, step func(values, next []float32)) {
+	if len(s.values) != len(field) {
+		s.values = make([]float32, len(field))
+		s.next = make([]float32, len(field))
+	}
+
+	for i, v := range field {
+		s.values[i] = *v
+	}
+
+	step(s.values, s.next)
+
+	for i, v := range field {
+		*v = s.next[i]
+	}
+}
+
+// Index the values of every cell into flat slices
+func (a *Atmosphere) index() {
+	cells := a.GetCells()
+
+	a.quantities = make(map[chem.ElementType][]*float32)
+	a.temperatures = make([]*float32, len(cells))
+	a.winds = make([]*math32.Vector3, len(cells))
+
+	for _, element := range chem.ElementTypes {
+		a.quantities[element] = make([]*float32, len(cells))
+	}
+
+	for i, cell := range cells {
+		a.temperatures[i] = &cell.Temperature
+		a.winds[i] = &cell.Wind
+
+		for _, element := range chem.ElementTypes {
+			a.quantities[element][i] = &cell.Quantities[element].Value
 		}
 	}
 }
 
+// Precipitate removes a fraction of the water vapour that each cell in the column at x, z can't hold at its temperature,
+// starting at height fromY. Returns the litres of water that fell out of the column.
+func (a *Atmosphere) Precipitate(x, z, fromY int, fraction float32) (total float32) {
+	w := a.Cfg.Simulation.Width
+	h := a.Cfg.Simulation.Height
+	vapour := a.quantities[chem.Water]
+
+	for y := max(0, fromY); y < h; y++ {
+		i := x + w*(y+h*z)
+
+		if excess := *vapour[i] - chem.SaturationHumidity(*a.temperatures[i]); excess > 0 {
+			amount := excess * fraction
+			*vapour[i] -= amount
+			total += amount
+		}
+	}
+
+	return
+}
+
+// SetGround sets the height of the lowest cell above the ground in the column at x, z, the wind can't blow through the cells below it.
+// The top cell of a column is always left open so that the wind has somewhere to go.
+func (a *Atmosphere) SetGround(x, z, y int) {
+	a.ground[x+a.Cfg.Simulation.Width*z] = max(0, min(y, a.Cfg.Simulation.Height-1))
+}
+
+// Cell returns the cell at x, y, z
+func (a Atmosphere) Cell(x, y, z int) *state.Cell {
+	return a.cells[x][y][z]
+}
+
 // GetCells returns the atmosphere as a linear slice of cells
 func (a Atmosphere) GetCells() []*state.Cell {
 	w := a.Cfg.Simulation.Width
@@ -102,7 +307,10 @@ func (a *Atmosphere) SetCells(cells []*state.Cell) {
 		for y := 0; y < h; y++ {
 			for z := 0; z < d; z++ {
 				a.cells[x][y][z] = cells[x+w*(y+h*z)]
+				fill(a.cells[x][y][z])
 			}
 		}
 	}
+
+	a.index()
 }
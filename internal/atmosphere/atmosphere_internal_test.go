@@ -0,0 +1,344 @@
+package atmosphere
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"cbeimers113/strands/internal/chem"
+	"cbeimers113/strands/internal/config"
+)
+
+// Create a small config so that tests don't depend on the user's config file
+func testConfig() *config.Config {
+	cfg := &config.Config{Name: "Strands Test"}
+	cfg.Simulation.Width = 6
+	cfg.Simulation.Height = 5
+	cfg.Simulation.Depth = 4
+	cfg.Simulation.Speed = 24
+	cfg.Simulation.TickLength = 12
+
+	return cfg
+}
+
+func sum(values []float32) (total float64) {
+	for _, v := range values {
+		total += float64(v)
+	}
+
+	return
+}
+
+func Test_diffuse(t *testing.T) {
+	const w, h, d = 4, 3, 5
+
+	uniform := make([]float32, w*h*d)
+	spike := make([]float32, w*h*d)
+	noise := make([]float32, w*h*d)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := range uniform {
+		uniform[i] = 3
+		noise[i] = rng.Float32() * 100
+	}
+	spike[w*h*d/2] = 1000
+
+	tests := []struct {
+		name   string
+		values []float32
+		k      float32
+	}{
+		{
+			name:   "Happy path - uniform field",
+			values: uniform,
+			k:      0.1,
+		},
+		{
+			name:   "Happy path - single spike",
+			values: spike,
+			k:      0.1,
+		},
+		{
+			name:   "Happy path - noisy field",
+			values: noise,
+			k:      0.05,
+		},
+		{
+			name:   "Happy path - a coefficient too large for an explicit step stays stable",
+			values: spike,
+			k:      10,
+		},
+		{
+			name:   "Sad path - negative coefficient does nothing",
+			values: noise,
+			k:      -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := append([]float32{}, tt.values...)
+			next := make([]float32, len(values))
+			total := sum(values)
+
+			var hi float32
+			for _, v := range values {
+				hi = max(hi, v)
+			}
+
+			for i := 0; i < 200; i++ {
+				diffuse(values, next, w, h, d, tt.k)
+				values, next = next, values
+
+				// Diffusion never creates new extremes
+				for _, v := range values {
+					assert.GreaterOrEqual(t, v, float32(-1e-4))
+					assert.LessOrEqual(t, v, hi+1e-3)
+				}
+			}
+
+			assert.InDelta(t, total, sum(values), total*1e-5+1e-6)
+		})
+	}
+}
+
+func Test_Update(t *testing.T) {
+	tests := []struct {
+		name      string
+		deltaTime float32
+		ticks     int
+		wind      float32
+	}{
+		{
+			name:      "Happy path - normal tick length",
+			deltaTime: 1000.0 / 24,
+			ticks:     500,
+		},
+		{
+			name:      "Happy path - very long ticks",
+			deltaTime: 60_000,
+			ticks:     50,
+		},
+		{
+			name:      "Happy path - strong prevailing wind",
+			deltaTime: 1000.0 / 24,
+			ticks:     500,
+			wind:      config.MaxWindSpeed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Weather.WindSpeed = tt.wind
+			cfg.Weather.WindDirection = 30
+
+			a := New(cfg)
+			a.SetGround(2, 2, 3)
+			rng := rand.New(rand.NewSource(2))
+
+			// Perturb the starting atmosphere so that there is something to diffuse
+			for _, cell := range a.GetCells() {
+				cell.Quantities[chem.Water].Value = rng.Float32() * 5
+				cell.Temperature = rng.Float32()*40 - 10
+			}
+
+			totals := func() (water, temperature float64) {
+				for _, cell := range a.GetCells() {
+					water += float64(cell.Quantities[chem.Water].Value)
+					temperature += float64(cell.Temperature)
+				}
+
+				return
+			}
+
+			water, temperature := totals()
+			var blown float64
+			for i := 0; i < tt.ticks; i++ {
+				blown += float64(a.Update(tt.deltaTime)[chem.Water])
+			}
+			gotWater, gotTemperature := totals()
+
+			// Only the water that blew in over the edges of the map is gained or lost
+			assert.InDelta(t, water+blown, gotWater, 1e-2)
+			if tt.wind == 0 {
+				assert.Zero(t, blown)
+				assert.InDelta(t, temperature, gotTemperature, 1e-2)
+			} else {
+				assert.NotZero(t, blown)
+			}
+
+			// Nothing can be carried out of a cell that it doesn't hold
+			for _, cell := range a.GetCells() {
+				assert.GreaterOrEqual(t, cell.Quantities[chem.Water].Value, float32(0))
+			}
+		})
+	}
+}
+
+func Test_UpdateSplit(t *testing.T) {
+	tests := []struct {
+		name  string
+		wind  float32
+		split int // How many shorter updates the second atmosphere covers the same time in
+	}{
+		{
+			name:  "Happy path - still air",
+			split: 5,
+		},
+		{
+			name:  "Happy path - prevailing wind",
+			wind:  5,
+			split: 5,
+		},
+		{
+			name:  "Happy path - strong prevailing wind",
+			wind:  config.MaxWindSpeed,
+			split: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Weather.WindSpeed = tt.wind
+			cfg.Weather.WindDirection = 30
+
+			// Air of one temperature so that the wind doesn't change between updates, with a cloud of vapour in the middle
+			long, short := New(cfg), New(cfg)
+			for _, a := range []*Atmosphere{long, short} {
+				for _, cell := range a.GetCells() {
+					cell.Temperature = SeaAirTemperature
+				}
+				a.Cell(2, 2, 2).Quantities[chem.Water].Value = 100
+			}
+
+			long.Update(60_000)
+			for i := 0; i < tt.split; i++ {
+				short.Update(60_000 / float32(tt.split))
+			}
+
+			// However the time is split, the vapour is carried just as far
+			for i, cell := range long.GetCells() {
+				assert.InDelta(t, cell.Quantities[chem.Water].Value, short.GetCells()[i].Quantities[chem.Water].Value, 0.05)
+			}
+		})
+	}
+}
+
+func Test_updateWind(t *testing.T) {
+	tests := []struct {
+		name   string
+		wind   float32
+		ground map[[2]int]int
+	}{
+		{
+			name: "Happy path - still air over flat ground",
+		},
+		{
+			name: "Happy path - prevailing wind over flat ground",
+			wind: 5,
+		},
+		{
+			name:   "Happy path - prevailing wind over hills",
+			wind:   5,
+			ground: map[[2]int]int{{1, 1}: 2, {1, 2}: 1, {4, 3}: 4, {5, 0}: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Weather.WindSpeed = tt.wind
+			cfg.Weather.WindDirection = 60
+
+			w, h, d := cfg.Simulation.Width, cfg.Simulation.Height, cfg.Simulation.Depth
+			a := New(cfg)
+			rng := rand.New(rand.NewSource(3))
+
+			for pos, y := range tt.ground {
+				a.SetGround(pos[0], pos[1], y)
+			}
+
+			for _, cell := range a.GetCells() {
+				cell.Temperature = rng.Float32()*20 + 10
+			}
+
+			fastest := a.updateWind()
+			assert.GreaterOrEqual(t, fastest, float32(0))
+
+			// As much air flows out of every cell as flows into it, and none flows through the ground
+			for z := 0; z < d; z++ {
+				for y := 0; y < h; y++ {
+					for x := 0; x < w; x++ {
+						i := x + w*(y+h*z)
+						westward, northward := a.edgeX[y+h*z], a.edgeZ[x+w*y]
+						if x > 0 {
+							westward = a.windX[i-1]
+						}
+						if z > 0 {
+							northward = a.windZ[i-w*h]
+						}
+
+						net := a.windX[i] - westward + a.windZ[i] - northward + a.windY[i]
+						if y > 0 {
+							net -= a.windY[i-w]
+						}
+
+						assert.InDelta(t, 0, net, 1e-3)
+
+						if y < a.ground[x+w*z] {
+							assert.Zero(t, a.windX[i])
+							assert.Zero(t, a.windY[i])
+							assert.Zero(t, a.windZ[i])
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func Test_Load(t *testing.T) {
+	cfg := testConfig()
+
+	// A save from before the gases were added only has water in its cells
+	cells := New(cfg).GetCells()
+	for _, cell := range cells {
+		cell.Quantities = map[chem.ElementType]*chem.Quantity{chem.Water: {Value: 0.5, Units: chem.Litre}}
+	}
+
+	a := Load(cfg, cells)
+	for i, cell := range a.GetCells() {
+		assert.Same(t, cells[i], cell)
+		assert.Equal(t, float32(0.5), cell.Quantities[chem.Water].Value)
+
+		for _, element := range chem.Elements {
+			if element.Type == chem.Water {
+				continue
+			}
+
+			assert.Equal(t, chem.Quantity{Value: element.Starting, Units: element.Units}, *cell.Quantities[element.Type])
+		}
+	}
+
+	totals := func() map[chem.ElementType]float64 {
+		totals := make(map[chem.ElementType]float64)
+		for _, cell := range a.GetCells() {
+			for element, quantity := range cell.Quantities {
+				totals[element] += float64(quantity.Value)
+			}
+		}
+
+		return totals
+	}
+
+	// Updating the loaded atmosphere moves every element around without losing or gaining any
+	before := totals()
+	a.Update(1000.0 / 24)
+
+	for _, element := range chem.ElementTypes {
+		assert.InDelta(t, before[element], totals()[element], before[element]*1e-5+1e-6)
+	}
+}
@@ -0,0 +1,136 @@
+package atmosphere
+
+import (
+	"math"
+)
+
+var (
+	// The fraction of the difference in an element's quantity that is exchanged between two neighbouring cells per second
+	ElementDiffusivity float32 = 0.5
+
+	// The fraction of the difference in temperature that is exchanged between two neighbouring cells per second
+	HeatDiffusivity float32 = 1.0
+)
+
+// The smallest share of a cell's difference from another cell that a kernel bothers to move, anything less is left out
+const kernelCutoff = 1e-5
+
+// A heat kernel for a row of n cells, the share of the difference between cells i and j that is exchanged is weights[i*n+j].
+// Every weight further than radius from the diagonal is too small to matter.
+type kernel struct {
+	weights []float32
+	n       int
+	radius  int
+}
+
+// A diffusion of a w*h*d grid by the same k along each axis
+type diffusion struct {
+	x, y, z kernel
+}
+
+// Diffuse a w*h*d grid of values into next with closed boundaries, where every face between two cells exchanges k times
+// their difference, as it changes over the step. The exchange is solved exactly rather than stepped, so it's stable however
+// large k is and diffusing by k once is the same as diffusing by k/2 twice. On a box the three axes diffuse independently,
+// so each row of cells is diffused along x, then y, then z by its heat kernel, which conserves the total over the grid.
+func diffuse(values, next []float32, w, h, d int, k float32) {
+	newDiffusion(w, h, d, k).apply(values, next)
+}
+
+// Work out the heat kernels that diffuse a w*h*d grid by k, so they can be shared by every field diffused by the same amount
+func newDiffusion(w, h, d int, k float32) diffusion {
+	return diffusion{x: heatKernel(w, k), y: heatKernel(h, k), z: heatKernel(d, k)}
+}
+
+// Diffuse a grid of values into next, see diffuse
+func (f diffusion) apply(values, next []float32) {
+	copy(next, values)
+
+	w, h, d := f.x.n, f.y.n, f.z.n
+	row := make([]float32, max(w, h, d))
+
+	for z := 0; z < d; z++ {
+		for y := 0; y < h; y++ {
+			f.x.spread(next, w*(y+h*z), 1, row[:w])
+		}
+	}
+
+	for z := 0; z < d; z++ {
+		for x := 0; x < w; x++ {
+			f.y.spread(next, x+w*h*z, w, row[:h])
+		}
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			f.z.spread(next, x+w*y, w*h, row[:d])
+		}
+	}
+}
+
+// Get the kernel that diffuses a row of n cells with closed ends by k, built from the row's cosine modes.
+// Each mode decays by its own rate, the smoothest never decays so the total is kept, and sharper ones die off faster.
+func heatKernel(n int, k float32) kernel {
+	if k <= 0 {
+		return kernel{weights: make([]float32, n*n), n: n}
+	}
+
+	modes := make([]float64, n*n)
+	for m := 0; m < n; m++ {
+		for i := 0; i < n; i++ {
+			modes[m*n+i] = math.Cos(math.Pi * float64(m) * (float64(i) + 0.5) / float64(n))
+		}
+	}
+
+	weights := make([]float64, n*n)
+	for m := 0; m < n; m++ {
+		decay := math.Exp(-float64(k) * 2 * (1 - math.Cos(math.Pi*float64(m)/float64(n))))
+		scale := 2 / float64(n)
+		if m == 0 {
+			scale = 1 / float64(n)
+		}
+
+		mode := modes[m*n : (m+1)*n]
+		for i := 0; i < n; i++ {
+			for j := 0; j <= i; j++ {
+				weights[i*n+j] += decay * scale * mode[i] * mode[j]
+			}
+		}
+	}
+
+	// Only the lower half is worked out, so the kernel is exactly symmetric as spread relies on
+	out := kernel{weights: make([]float32, n*n), n: n}
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			weight := float32(weights[i*n+j])
+			if i != j && math.Abs(weights[i*n+j]) < kernelCutoff {
+				weight = 0
+			}
+
+			out.weights[i*n+j], out.weights[j*n+i] = weight, weight
+			if weight != 0 {
+				out.radius = max(out.radius, i-j)
+			}
+		}
+	}
+
+	return out
+}
+
+// Diffuse the values in a row in place, where the row starts at start and its cells are stride apart.
+// Each cell gains the kernel's share of its difference from every other cell in the row. The kernel is symmetric,
+// so what one cell gains the other loses and rounding can't creep into the total. Row is scratch space as long as the row.
+func (k kernel) spread(values []float32, start, stride int, row []float32) {
+	for i := range row {
+		row[i] = values[start+i*stride]
+	}
+
+	for i, value := range row {
+		lo, hi := max(0, i-k.radius), min(k.n, i+k.radius+1)
+		sum := value
+		for j, weight := range k.weights[i*k.n+lo : i*k.n+hi] {
+			sum += weight * (row[lo+j] - value)
+		}
+
+		values[start+i*stride] = sum
+	}
+}
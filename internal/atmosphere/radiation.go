@@ -0,0 +1,23 @@
+package atmosphere
+
+var (
+	// The temperature in °C that the clear night sky radiates at, the air and the ground can't radiate themselves any colder
+	SkyTemperature float32 = 0
+
+	// The fraction of the difference between the air's temperature and the sky's that the air radiates away per day
+	AirRadiation float32 = 0.1
+)
+
+// The energy in J it takes to warm a cell's air by 1°C. The atmosphere is only as tall as the map, so each cell
+// stands in for a much thicker layer of real air than its cubic metre, otherwise the ground would cook it in an afternoon.
+const AirHeatCapacity float32 = 150_000
+
+// Radiate heat from every cell out to the sky over a number of seconds of simulated time.
+// The air is only warmed from the ground, so this is what keeps it colder the higher up it is.
+func (a *Atmosphere) Radiate(seconds float32) {
+	k := min(1, AirRadiation*seconds/(60*60*24))
+
+	for _, t := range a.temperatures {
+		*t -= k * (*t - SkyTemperature)
+	}
+}
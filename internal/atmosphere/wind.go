@@ -0,0 +1,249 @@
+package atmosphere
+
+import (
+	"github.com/g3n/engine/math32"
+)
+
+// How strongly air is drawn towards warmer air beside it, in m/s for each °C of difference between two cells
+var ThermalWind float32 = 0.5
+
+// The furthest the air can carry a cell's contents out of it in one step, as a fraction of the cell
+const maxCourant float32 = 0.9
+
+// Work out the wind across every face between two cells from the prevailing wind and the temperature of the air.
+// Air is drawn along the ground towards warmer air and returns higher up, and the air that can't flow through
+// the ground is squeezed over it. Whatever flows into a cell sideways leaves it upwards or downwards, so the wind
+// never piles up air anywhere, and the winds are what move the contents of the cells in advect.
+// The prevailing wind blows in over the edges of the map on one side and out over the other.
+// Returns the fastest that the air leaves any cell, in cells per second.
+func (a *Atmosphere) updateWind() float32 {
+	w := a.Cfg.Simulation.Width
+	h := a.Cfg.Simulation.Height
+	d := a.Cfg.Simulation.Depth
+
+	direction := a.Cfg.Weather.WindDirection * math32.Pi / 180
+	prevailingX := a.Cfg.Weather.WindSpeed * math32.Cos(direction)
+	prevailingZ := a.Cfg.Weather.WindSpeed * math32.Sin(direction)
+
+	if len(a.windX) != w*h*d {
+		a.windX = make([]float32, w*h*d)
+		a.windY = make([]float32, w*h*d)
+		a.windZ = make([]float32, w*h*d)
+		a.edgeX = make([]float32, h*d)
+		a.edgeZ = make([]float32, w*h)
+		a.warmth = make([]float32, w*h*d)
+	}
+
+	for i, t := range a.temperatures {
+		a.warmth[i] = *t
+	}
+
+	// Horizontal winds, between each cell and the next one along the x and z axes.
+	// The last faces along each axis and the edge faces before the first cells open onto the sea beyond the map.
+	for z := 0; z < d; z++ {
+		for x := 0; x < w; x++ {
+			if x+1 < w {
+				a.sideWind(a.windX, x, z, x+1, z, prevailingX)
+			} else {
+				a.edgeWind(a.windX, x, z, func(y int) int { return x + w*(y+h*z) }, prevailingX)
+			}
+
+			if z+1 < d {
+				a.sideWind(a.windZ, x, z, x, z+1, prevailingZ)
+			} else {
+				a.edgeWind(a.windZ, x, z, func(y int) int { return x + w*(y+h*z) }, prevailingZ)
+			}
+
+			if x == 0 {
+				a.edgeWind(a.edgeX, x, z, func(y int) int { return y + h*z }, prevailingX)
+			}
+
+			if z == 0 {
+				a.edgeWind(a.edgeZ, x, z, func(y int) int { return x + w*y }, prevailingZ)
+			}
+		}
+	}
+
+	// Vertical winds, carrying whatever flows into each cell from the sides and below on to the cell above it
+	var fastest float32
+	for z := 0; z < d; z++ {
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				i := x + w*(y+h*z)
+
+				// The faces on the negative side of the cell belong to the previous cell along each axis, or the edge of the map
+				var westward, northward float32
+				if x > 0 {
+					westward = a.windX[i-1]
+				} else {
+					westward = a.edgeX[y+h*z]
+				}
+
+				if z > 0 {
+					northward = a.windZ[i-w*h]
+				} else {
+					northward = a.edgeZ[x+w*y]
+				}
+
+				var below float32
+				if y > 0 {
+					below = a.windY[i-w]
+				}
+
+				a.windY[i] = 0
+				if y >= a.ground[x+w*z] && y+1 < h {
+					a.windY[i] = below + westward - a.windX[i] + northward - a.windZ[i]
+				}
+
+				// Record the wind through the middle of the cell
+				*a.winds[i] = math32.Vector3{
+					X: (westward + a.windX[i]) / 2,
+					Y: (below + a.windY[i]) / 2,
+					Z: (northward + a.windZ[i]) / 2,
+				}
+
+				// Find the fastest that the air leaves any cell
+				out := outflow(a.windX[i]) + outflow(-westward) +
+					outflow(a.windZ[i]) + outflow(-northward) +
+					outflow(a.windY[i]) + outflow(-below)
+				if out > fastest {
+					fastest = out
+				}
+			}
+		}
+	}
+
+	return fastest
+}
+
+// Get how fast the air leaves a cell across a face with a given wind out of it
+func outflow(wind float32) float32 {
+	if wind > 0 {
+		return wind
+	}
+
+	return 0
+}
+
+// Work out the wind across each face between the column of cells at x, z and the one at nextX, nextZ,
+// storing it in the wind slice at the cells of the first column. Only faces above the ground in both columns are open.
+func (a *Atmosphere) sideWind(wind []float32, x, z, nextX, nextZ int, prevailing float32) {
+	w := a.Cfg.Simulation.Width
+	h := a.Cfg.Simulation.Height
+	ground := max(a.ground[x+w*z], a.ground[nextX+w*nextZ])
+	open := h - ground
+
+	// The temperature difference across each open face, and their average up the column
+	var mean float32
+	for y := 0; y < h; y++ {
+		i := x + w*(y+h*z)
+		wind[i] = 0
+
+		if y >= ground {
+			wind[i] = a.warmth[nextX+w*(y+h*nextZ)] - a.warmth[i]
+			mean += wind[i] / float32(open)
+		}
+	}
+
+	// The prevailing wind speeds up to squeeze through the open faces, and the air drawn towards the warmer column
+	// along some faces returns along the others, so that the same amount of air crosses between every pair of columns
+	for y := ground; y < h; y++ {
+		i := x + w*(y+h*z)
+		wind[i] = prevailing*float32(h)/float32(open) + ThermalWind*(wind[i]-mean)
+	}
+}
+
+// Work out the wind across the faces between the column of cells at x, z and the open sea beyond the edge of the map,
+// storing it in the wind slice at the index of each height. The sea air is the same temperature as the column,
+// so only the prevailing wind crosses the edge, squeezed through the faces above the column's ground.
+func (a *Atmosphere) edgeWind(wind []float32, x, z int, index func(y int) int, prevailing float32) {
+	h := a.Cfg.Simulation.Height
+	ground := a.ground[x+a.Cfg.Simulation.Width*z]
+
+	for y := 0; y < h; y++ {
+		wind[index(y)] = 0
+		if y >= ground {
+			wind[index(y)] = prevailing * float32(h) / float32(h-ground)
+		}
+	}
+}
+
+// Carry a per-cell value along the wind over a number of seconds, using a donor cell scheme.
+// Each face moves the contents of the cell upwind of it, so whatever leaves a cell arrives in its neighbour.
+// The air that blows in over the edges of the map carries the sea air's value at its height, given by outside,
+// and the air that blows out carries its cell's value away. Returns the net amount that blew in over the edges.
+func (a *Atmosphere) advect(values, next, outside []float32, seconds float32) (blown float64) {
+	w := a.Cfg.Simulation.Width
+	h := a.Cfg.Simulation.Height
+	d := a.Cfg.Simulation.Depth
+	copy(next, values)
+
+	// Only visit the faces in the positive direction so that each face is crossed once,
+	// a row at a time so that the cells on either side of the faces are read in order
+	for z := 0; z < d; z++ {
+		for y := 0; y < h; y++ {
+			row := w * (y + h*z)
+			carry(values, next, a.windX, row, row+w-1, 1, seconds)
+
+			blown += cross(values, next, outside[y], row, a.edgeX[y+h*z], seconds)
+			blown += cross(values, next, outside[y], row+w-1, -a.windX[row+w-1], seconds)
+		}
+
+		carry(values, next, a.windY, w*h*z, w*h*z+w*(h-1), w, seconds)
+	}
+
+	carry(values, next, a.windZ, 0, w*h*(d-1), w*h, seconds)
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			first, last := x+w*y, x+w*(y+h*(d-1))
+			blown += cross(values, next, outside[y], first, a.edgeZ[x+w*y], seconds)
+			blown += cross(values, next, outside[y], last, -a.windZ[last], seconds)
+		}
+	}
+
+	return
+}
+
+// Move the contents of the upwind side across a face between the sea beyond the edge of the map and the cell inside it,
+// the wind across the face blows inwards when it's positive. Returns the amount that crossed inwards.
+func cross(values, next []float32, outside float32, cell int, wind, seconds float32) float64 {
+	courant := wind * seconds
+
+	var flux float32
+	if courant > 0 {
+		flux = courant * outside
+	} else {
+		flux = courant * values[cell]
+	}
+
+	next[cell] += flux
+	return float64(flux)
+}
+
+// Move the contents of the upwind cell across the faces between each cell from start up to end and the cell offset after it,
+// the wind across each face carries the air from the first cell towards the second
+func carry(values, next, wind []float32, start, end, offset int, seconds float32) {
+	if end <= start {
+		return
+	}
+
+	from, to := values[start:end], values[start+offset:end+offset]
+	nextFrom, nextTo := next[start:end], next[start+offset:end+offset]
+	wind = wind[start:end]
+	to, nextFrom, nextTo, wind = to[:len(from)], nextFrom[:len(from)], nextTo[:len(from)], wind[:len(from)]
+
+	for i, v := range from {
+		courant := wind[i] * seconds
+
+		var flux float32
+		if courant > 0 {
+			flux = courant * v
+		} else {
+			flux = courant * to[i]
+		}
+
+		nextFrom[i] -= flux
+		nextTo[i] += flux
+	}
+}
@@ -3,8 +3,62 @@ package chem
 // Represents elements that exist dynamically in the world (eg. non-tile types)
 type ElementType = string
 
-const Water ElementType = "water"
+const (
+	Water         ElementType = "water"
+	Oxygen        ElementType = "oxygen"
+	CarbonDioxide ElementType = "carbon dioxide"
+	Nitrogen      ElementType = "nitrogen"
+	Smoke         ElementType = "smoke"
+)
 
-var ElementTypes []ElementType = []ElementType{
-	Water,
+// Water held in the atmosphere, in the soil and frozen as ice and snow is tracked separately from water on the surface
+// in the simulation's totals. The cells of the atmosphere store it under Water like any other element.
+const (
+	WaterVapour ElementType = "water vapour"
+	SoilWater   ElementType = "soil water"
+	Ice         ElementType = "ice"
+)
+
+// The mass in g of a cubic metre of dry air at 20°C and sea level pressure
+const airDensity float32 = 1204
+
+// Element describes an element that the simulation tracks through the atmosphere
+type Element struct {
+	Type      ElementType
+	Formula   string  // The chemical formula of the element
+	Units     Unit    // The units that quantities of the element are measured in
+	MolarMass float32 // The mass of a mole of the element in g
+	Starting  float32 // How much of the element a cubic metre of air starts out with, in the element's units
+}
+
+// Every element in the atmosphere, adding an element to the simulation only takes a new entry here.
+// The starting amounts of the gases are their share of the mass of dry air, water vapour depends on the temperature instead.
+var Elements []Element = []Element{
+	{Type: Water, Formula: "H₂O", Units: Litre, MolarMass: 18.015},
+	{Type: Nitrogen, Formula: "N₂", Units: Gram, MolarMass: 28.014, Starting: 0.7552 * airDensity},
+	{Type: Oxygen, Formula: "O₂", Units: Gram, MolarMass: 31.998, Starting: 0.2314 * airDensity},
+	{Type: CarbonDioxide, Formula: "CO₂", Units: Gram, MolarMass: 44.009, Starting: 0.00064 * airDensity},
+	{Type: Smoke, Formula: "C", Units: Gram, MolarMass: 12.011}, // The soot given off by fires, clean air has none
+}
+
+var ElementTypes []ElementType = elementTypes()
+
+// List the type of every element in the order they're declared
+func elementTypes() (types []ElementType) {
+	for _, element := range Elements {
+		types = append(types, element.Type)
+	}
+
+	return
+}
+
+// ElementOf returns the description of an element type, and whether the simulation tracks it
+func ElementOf(elementType ElementType) (Element, bool) {
+	for _, element := range Elements {
+		if element.Type == elementType {
+			return element, true
+		}
+	}
+
+	return Element{}, false
 }
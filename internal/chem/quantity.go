@@ -1,35 +1,199 @@
 package chem
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // The various measurement units
 type Unit string
 
 const (
-	Celcius Unit = "°C"
-	Litre   Unit = "L"
-	Gram    Unit = "g"
-	Metre   Unit = "m"
+	Celcius    Unit = "°C"
+	Kelvin     Unit = "K"
+	Fahrenheit Unit = "°F"
+	Millilitre Unit = "mL"
+	Litre      Unit = "L"
+	CubicMetre Unit = "m³"
+	Gram       Unit = "g"
+	Kilogram   Unit = "kg"
+	Tonne      Unit = "t"
+	Metre      Unit = "m"
+	Mole       Unit = "mol"
 )
 
+// The kind of thing a unit measures, quantities can only be converted between units of the same dimension
+type Dimension string
+
+const (
+	Temperature Dimension = "temperature"
+	Volume      Dimension = "volume"
+	Mass        Dimension = "mass"
+	Length      Dimension = "length"
+	Amount      Dimension = "amount of substance"
+)
+
+// How a unit relates to the base unit of its dimension, a value in the unit is worth value*scale + offset base units.
+// The base units are K, L, g, m and mol.
+type unitInfo struct {
+	dimension Dimension
+	scale     float64
+	offset    float64
+}
+
+var units = map[Unit]unitInfo{
+	Celcius:    {Temperature, 1, 273.15},
+	Kelvin:     {Temperature, 1, 0},
+	Fahrenheit: {Temperature, 5.0 / 9, 273.15 - 32*5.0/9},
+	Millilitre: {Volume, 0.001, 0},
+	Litre:      {Volume, 1, 0},
+	CubicMetre: {Volume, 1000, 0},
+	Gram:       {Mass, 1, 0},
+	Kilogram:   {Mass, 1000, 0},
+	Tonne:      {Mass, 1_000_000, 0},
+	Metre:      {Length, 1, 0},
+	Mole:       {Amount, 1, 0},
+}
+
+// The units that readable quantities are scaled between, from largest to smallest
+var scales = map[Dimension][]Unit{
+	Volume: {CubicMetre, Litre, Millilitre},
+	Mass:   {Tonne, Kilogram, Gram},
+}
+
+// The units to show quantities in by dimension, dimensions without a preference are shown in whichever of their units reads best.
+// Temperatures without a preference are shown in the units they're stored in.
+var DisplayUnits = map[Dimension]Unit{}
+
 // Represents an amount of an element
 type Quantity struct {
 	Value float32 `json:"value"`
 	Units Unit    `json:"units"`
 }
 
-// Create a string representation of a quantity
-func (q Quantity) String() (str string) {
+// Dimension returns what a unit measures, or an empty dimension if the unit isn't known
+func (u Unit) Dimension() Dimension {
+	return units[u].dimension
+}
+
+// Create a string representation of a quantity in the preferred display units
+func (q Quantity) String() string {
+	return q.Format(DisplayUnits)
+}
+
+// Format a quantity in the units preferred for its dimension, or in whichever of its units reads best if there's no preference
+func (q Quantity) Format(display map[Dimension]Unit) string {
+	if preferred, ok := display[q.Units.Dimension()]; ok && preferred != "" {
+		if converted, err := q.Convert(preferred); err == nil {
+			q = converted
+		}
+	} else {
+		q = q.Readable()
+	}
+
 	space := " "
 
 	// Exception to spacing between value and unit is degrees
-	if q.Units == Celcius {
+	if q.Units == Celcius || q.Units == Fahrenheit {
 		space = ""
 	}
 
-	str = fmt.Sprintf("%.2f%s%s", q.Value, space, q.Units)
+	return fmt.Sprintf("%.2f%s%s", q.Value, space, q.Units)
+}
+
+// Readable returns the quantity in the largest unit of its kind that leaves at least one of it,
+// so that the total mass of a gas in the atmosphere is shown in tonnes instead of hundreds of millions of grams
+func (q Quantity) Readable() Quantity {
+	scale := scales[q.Units.Dimension()]
+
+	for i, unit := range scale {
+		if converted, err := q.Convert(unit); err == nil && (math.Abs(float64(converted.Value)) >= 1 || i == len(scale)-1) {
+			return converted
+		}
+	}
+
+	return q
+}
+
+// Convert returns the quantity in different units of the same dimension
+func (q Quantity) Convert(to Unit) (Quantity, error) {
+	return q.convert(to, true)
+}
+
+// Convert a quantity that's the difference between two values to different units of the same dimension.
+// Differences only scale between units, so a rise of 10 K is a rise of 10°C or 18°F.
+func (q Quantity) convertDifference(to Unit) (Quantity, error) {
+	return q.convert(to, false)
+}
+
+// Convert a quantity to different units of the same dimension, offsets is whether the zero points of the units are accounted for
+func (q Quantity) convert(to Unit, offsets bool) (Quantity, error) {
+	from, ok := units[q.Units]
+	if !ok {
+		return q, fmt.Errorf("unknown unit [%s]", q.Units)
+	}
+
+	target, ok := units[to]
+	if !ok {
+		return q, fmt.Errorf("unknown unit [%s]", to)
+	}
+
+	if from.dimension != target.dimension {
+		return q, fmt.Errorf("can't convert %s [%s] to %s [%s]", from.dimension, q.Units, target.dimension, to)
+	}
+
+	if !offsets {
+		return Quantity{Value: float32(float64(q.Value) * from.scale / target.scale), Units: to}, nil
+	}
+
+	base := float64(q.Value)*from.scale + from.offset
+	return Quantity{Value: float32((base - target.offset) / target.scale), Units: to}, nil
+}
+
+// Add returns the sum of two quantities of the same dimension in the units of the first.
+// The second quantity is taken as a difference, so 20°C + 10 K is 30°C.
+func (q Quantity) Add(other Quantity) (Quantity, error) {
+	converted, err := other.convertDifference(q.Units)
+	if err != nil {
+		return q, fmt.Errorf("can't add [%s] to [%s]: %w", other, q, err)
+	}
+
+	return Quantity{Value: q.Value + converted.Value, Units: q.Units}, nil
+}
+
+// Sub returns the difference of two quantities of the same dimension in the units of the first.
+// The second quantity is taken as a difference, so 20°C - 18°F is 10°C.
+func (q Quantity) Sub(other Quantity) (Quantity, error) {
+	converted, err := other.convertDifference(q.Units)
+	if err != nil {
+		return q, fmt.Errorf("can't subtract [%s] from [%s]: %w", other, q, err)
+	}
+
+	return Quantity{Value: q.Value - converted.Value, Units: q.Units}, nil
+}
+
+// Scale returns the quantity multiplied by a factor
+func (q Quantity) Scale(factor float32) Quantity {
+	return Quantity{Value: q.Value * factor, Units: q.Units}
+}
+
+// Moles returns how many moles of a substance with a molar mass in g/mol a quantity of mass holds
+func (q Quantity) Moles(molarMass float32) (Quantity, error) {
+	grams, err := q.Convert(Gram)
+	if err != nil {
+		return q, err
+	}
+
+	return Quantity{Value: grams.Value / molarMass, Units: Mole}, nil
+}
 
-	return
+// Mass returns how much a number of moles of a substance with a molar mass in g/mol weighs, in the given units of mass
+func (q Quantity) Mass(molarMass float32, to Unit) (Quantity, error) {
+	if q.Units != Mole {
+		return q, fmt.Errorf("can't weigh [%s], it isn't an amount of substance", q)
+	}
+
+	return Quantity{Value: q.Value * molarMass, Units: Gram}.Convert(to)
 }
 
 // Convert from litres to cubic metres (dimensions of one tile is 1 cubic metre)
@@ -41,3 +205,8 @@ func LitresToCubicMetres(litres float32) float32 {
 func CubicMetresToLitres(cubicMetres float32) float32 {
 	return cubicMetres * 1000
 }
+
+// Convert a temperature in °C to K
+func CelciusToKelvin(celcius float32) float32 {
+	return celcius + 273.15
+}
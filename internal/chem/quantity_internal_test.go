@@ -0,0 +1,161 @@
+package chem
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Convert(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity Quantity
+		to       Unit
+		want     Quantity
+		err      error
+	}{
+		{
+			name:     "Happy path - celcius to kelvin",
+			quantity: Quantity{Value: 20, Units: Celcius},
+			to:       Kelvin,
+			want:     Quantity{Value: 293.15, Units: Kelvin},
+		},
+		{
+			name:     "Happy path - fahrenheit to celcius",
+			quantity: Quantity{Value: 212, Units: Fahrenheit},
+			to:       Celcius,
+			want:     Quantity{Value: 100, Units: Celcius},
+		},
+		{
+			name:     "Happy path - cubic metres to millilitres",
+			quantity: Quantity{Value: 0.5, Units: CubicMetre},
+			to:       Millilitre,
+			want:     Quantity{Value: 500_000, Units: Millilitre},
+		},
+		{
+			name:     "Happy path - kilograms to grams",
+			quantity: Quantity{Value: 1.5, Units: Kilogram},
+			to:       Gram,
+			want:     Quantity{Value: 1500, Units: Gram},
+		},
+		{
+			name:     "Sad path - litres to grams",
+			quantity: Quantity{Value: 1, Units: Litre},
+			to:       Gram,
+			want:     Quantity{Value: 1, Units: Litre},
+			err:      fmt.Errorf("can't convert %s [%s] to %s [%s]", Volume, Litre, Mass, Gram),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.quantity.Convert(tt.to)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.want.Units, got.Units)
+			assert.InDelta(t, tt.want.Value, got.Value, 1e-3)
+		})
+	}
+}
+
+func Test_arithmetic(t *testing.T) {
+	sum, err := Quantity{Value: 2, Units: Litre}.Add(Quantity{Value: 1, Units: CubicMetre})
+	assert.NoError(t, err)
+	assert.Equal(t, Quantity{Value: 1002, Units: Litre}, sum)
+
+	_, err = Quantity{Value: 2, Units: Litre}.Sub(Quantity{Value: 1, Units: Celcius})
+	assert.Error(t, err)
+
+	moles, err := Quantity{Value: 36.03, Units: Gram}.Moles(18.015)
+	assert.NoError(t, err)
+	assert.InDelta(t, 2, moles.Value, 1e-4)
+
+	mass, err := moles.Mass(18.015, Kilogram)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.03603, mass.Value, 1e-6)
+}
+
+func Test_Add(t *testing.T) {
+	tests := []struct {
+		name  string
+		q     Quantity
+		other Quantity
+		sum   Quantity
+		diff  Quantity
+	}{
+		{
+			name:  "Happy path - kelvin to celcius",
+			q:     Quantity{Value: 20, Units: Celcius},
+			other: Quantity{Value: 10, Units: Kelvin},
+			sum:   Quantity{Value: 30, Units: Celcius},
+			diff:  Quantity{Value: 10, Units: Celcius},
+		},
+		{
+			name:  "Happy path - fahrenheit to celcius",
+			q:     Quantity{Value: 20, Units: Celcius},
+			other: Quantity{Value: 18, Units: Fahrenheit},
+			sum:   Quantity{Value: 30, Units: Celcius},
+			diff:  Quantity{Value: 10, Units: Celcius},
+		},
+		{
+			name:  "Happy path - celcius to fahrenheit",
+			q:     Quantity{Value: 50, Units: Fahrenheit},
+			other: Quantity{Value: 5, Units: Celcius},
+			sum:   Quantity{Value: 59, Units: Fahrenheit},
+			diff:  Quantity{Value: 41, Units: Fahrenheit},
+		},
+		{
+			name:  "Happy path - celcius to kelvin",
+			q:     Quantity{Value: 300, Units: Kelvin},
+			other: Quantity{Value: 20, Units: Celcius},
+			sum:   Quantity{Value: 320, Units: Kelvin},
+			diff:  Quantity{Value: 280, Units: Kelvin},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum, err := tt.q.Add(tt.other)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.sum.Units, sum.Units)
+			assert.InDelta(t, tt.sum.Value, sum.Value, 1e-3)
+
+			diff, err := tt.q.Sub(tt.other)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.diff.Units, diff.Units)
+			assert.InDelta(t, tt.diff.Value, diff.Value, 1e-3)
+		})
+	}
+}
+
+func Test_Format(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity Quantity
+		display  map[Dimension]Unit
+		want     string
+	}{
+		{
+			name:     "Happy path - no preference",
+			quantity: Quantity{Value: 2_500_000, Units: Gram},
+			want:     "2.50 t",
+		},
+		{
+			name:     "Happy path - small volume",
+			quantity: Quantity{Value: 0.25, Units: Litre},
+			want:     "250.00 mL",
+		},
+		{
+			name:     "Happy path - preferred temperature",
+			quantity: Quantity{Value: 100, Units: Celcius},
+			display:  map[Dimension]Unit{Temperature: Fahrenheit},
+			want:     "212.00°F",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.quantity.Format(tt.display))
+		})
+	}
+}
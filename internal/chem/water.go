@@ -0,0 +1,12 @@
+package chem
+
+import "math"
+
+// SaturationHumidity returns how many litres of liquid water one cubic metre of air can hold as vapour at a given temperature in °C
+func SaturationHumidity(celcius float32) float32 {
+	// Magnus approximation of the saturation vapour pressure in hPa
+	pressure := 6.112 * math.Exp(17.67*float64(celcius)/(float64(celcius)+243.5))
+
+	// Ideal gas law gives the vapour density in g/m³, and a litre of water weighs 1000 g
+	return float32(216.7*pressure/float64(CelciusToKelvin(celcius))) / 1000
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
+	"cbeimers113/strands/internal/chem"
 	"cbeimers113/strands/internal/io/file"
 )
 
@@ -17,23 +19,69 @@ type Config struct {
 	Fullscreen bool   `json:"fullscreen"`
 
 	Simulation struct {
-		Width     int `json:"-"`
-		Height    int `json:"-"`
-		Depth     int `json:"-"`
-		Speed     int `json:"ticks_per_second"`
-		DayLength int `json:"day_length_mins"`
+		Width      int `json:"-"`
+		Height     int `json:"-"`
+		Depth      int `json:"-"`
+		Speed      int `json:"ticks_per_second"`
+		TickLength int `json:"tick_length_secs"`          // How many seconds of simulated time pass in each tick, whatever the sim speed
+		DayLength  int `json:"day_length_mins,omitempty"` // How many minutes of real time a day lasted before ticks had a fixed length, only read to load old saves
 	} `json:"simulation"`
 
+	Calendar struct {
+		YearLength int     `json:"year_length_days"` // How many days there are in a year, which starts at the spring equinox
+		Latitude   float32 `json:"latitude"`         // How far north of the equator the map is in degrees, negative for south
+	} `json:"calendar"`
+
 	Controls struct {
 		MouseSensitivityX float32 `json:"mouse_sensitivity_x"`
 		MouseSensitivityY float32 `json:"mouse_sensitivity_y"`
 		MoveSpeed         float32 `json:"move_speed"`
 	} `json:"controls"`
+
+	Weather struct {
+		WindSpeed     float32 `json:"prevailing_wind_speed"`     // Speed of the prevailing wind in m/s
+		WindDirection float32 `json:"prevailing_wind_direction"` // Direction the prevailing wind blows towards, in degrees from the map's x axis towards its z axis
+	} `json:"weather"`
+
+	Water struct {
+		ShallowWater bool    `json:"shallow_water"` // Whether water flows with momentum, instead of evening out between neighbouring tiles
+		SeaLevel     float32 `json:"sea_level"`     // The height in metres of the surface of the ocean around the map
+	} `json:"water"`
+
+	Terrain struct {
+		Generator Generator `json:"generator"` // How the terrain of new simulations is generated
+		Octaves   int       `json:"octaves"`   // How many layers of ever finer noise are added together to make an island's terrain
+		Roughness float32   `json:"roughness"` // How much each layer of noise adds compared to the one before it, higher is rougher
+		Coastline float32   `json:"coastline"` // How irregular an island's coastline is, from 0 for a smooth oval to 1
+	} `json:"terrain"`
+
+	Units struct {
+		Temperature chem.Unit `json:"temperature"` // The units to show temperatures in, left empty to show them in °C
+		Volume      chem.Unit `json:"volume"`      // The units to show volumes in, left empty to pick whichever reads best
+		Mass        chem.Unit `json:"mass"`        // The units to show masses in, left empty to pick whichever reads best
+	} `json:"units"`
 }
 
+// Generator is a way of generating the terrain of a new simulation
+type Generator string
+
+const (
+	Island  Generator = "island"  // An island rising out of the ocean in the middle of the map
+	Classic Generator = "classic" // A single layer of noise stretched across the whole map
+)
+
+// Generators lists the terrain generators that can be picked for a new simulation
+var Generators = []Generator{Island, Classic}
+
 const (
 	Width, Height, Depth = 64, 64, 64
 
+	MaxTickLength = 60 * 60
+
+	MaxWindSpeed = 20
+
+	MaxOctaves = 8
+
 	errInvalidCfg = "invalid config: "
 )
 
@@ -46,7 +94,9 @@ func Load() (*Config, error) {
 		err  error
 	)
 
-	c = &Config{}
+	// Settings missing from the config file keep their default values
+	defaults := *defaultConfig
+	c = &defaults
 	configFilePath = filepath.Join(file.StoragePath, "config.json")
 
 	// Make sure a config file exists, otherwise use the default
@@ -75,6 +125,12 @@ func Load() (*Config, error) {
 	return c, nil
 }
 
+// Default returns a copy of the default config, as used when there's no config file
+func Default() *Config {
+	defaults := *defaultConfig
+	return &defaults
+}
+
 func (c Config) Save() error {
 	data, err := json.MarshalIndent(c, "", "	")
 	if err != nil {
@@ -107,8 +163,15 @@ func (c Config) validate() error {
 	if c.Simulation.Speed < 1 {
 		return fmt.Errorf("%ssimulation speed (TPS) [%d] too small", errInvalidCfg, c.Simulation.Speed)
 	}
-	if c.Simulation.DayLength < 1 {
-		return fmt.Errorf("%ssimulation day length too small: [%d minutes]", errInvalidCfg, c.Simulation.DayLength)
+	if c.Simulation.TickLength < 1 || c.Simulation.TickLength > MaxTickLength {
+		return fmt.Errorf("%ssimulation tick length [%d s] must be between 1 and %d s", errInvalidCfg, c.Simulation.TickLength, MaxTickLength)
+	}
+
+	if c.Calendar.YearLength < 4 {
+		return fmt.Errorf("%syear length [%d days] too short for four seasons", errInvalidCfg, c.Calendar.YearLength)
+	}
+	if c.Calendar.Latitude < -90 || c.Calendar.Latitude > 90 {
+		return fmt.Errorf("%slatitude must be between -90 and 90 degrees", errInvalidCfg)
 	}
 
 	if c.Controls.MouseSensitivityX <= 0 || c.Controls.MouseSensitivityX > 1 {
@@ -121,5 +184,42 @@ func (c Config) validate() error {
 		return fmt.Errorf("%smove speed must be between 0 and 1", errInvalidCfg)
 	}
 
+	if c.Weather.WindSpeed < 0 || c.Weather.WindSpeed > MaxWindSpeed {
+		return fmt.Errorf("%sprevailing wind speed must be between 0 and %d m/s", errInvalidCfg, MaxWindSpeed)
+	}
+
+	if c.Water.SeaLevel < 0 || c.Water.SeaLevel >= float32(c.Simulation.Height) {
+		return fmt.Errorf("%ssea level must be between 0 and %d m", errInvalidCfg, c.Simulation.Height)
+	}
+
+	display := c.DisplayUnits()
+	for _, dimension := range []chem.Dimension{chem.Temperature, chem.Volume, chem.Mass} {
+		if units := display[dimension]; units != "" && units.Dimension() != dimension {
+			return fmt.Errorf("%sdisplay units [%s] can't show %s", errInvalidCfg, units, dimension)
+		}
+	}
+
+	if !slices.Contains(Generators, c.Terrain.Generator) {
+		return fmt.Errorf("%sunknown terrain generator [%s]", errInvalidCfg, c.Terrain.Generator)
+	}
+	if c.Terrain.Octaves < 1 || c.Terrain.Octaves > MaxOctaves {
+		return fmt.Errorf("%sterrain octaves must be between 1 and %d", errInvalidCfg, MaxOctaves)
+	}
+	if c.Terrain.Roughness <= 0 || c.Terrain.Roughness > 1 {
+		return fmt.Errorf("%sterrain roughness must be between 0 and 1", errInvalidCfg)
+	}
+	if c.Terrain.Coastline < 0 || c.Terrain.Coastline > 1 {
+		return fmt.Errorf("%scoastline irregularity must be between 0 and 1", errInvalidCfg)
+	}
+
 	return nil
 }
+
+// Get the units the player prefers to see each dimension in
+func (c Config) DisplayUnits() map[chem.Dimension]chem.Unit {
+	return map[chem.Dimension]chem.Unit{
+		chem.Temperature: c.Units.Temperature,
+		chem.Volume:      c.Units.Volume,
+		chem.Mass:        c.Units.Mass,
+	}
+}
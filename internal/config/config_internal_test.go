@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"cbeimers113/strands/internal/chem"
 )
 
 func Test_validate(t *testing.T) {
@@ -19,17 +21,26 @@ func Test_validate(t *testing.T) {
 				Name: "Strands Test",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
+				}{
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
+				},
+
+				Calendar: struct {
+					YearLength int     `json:"year_length_days"`
+					Latitude   float32 `json:"latitude"`
 				}{
-					Width:     64,
-					Height:    64,
-					Depth:     64,
-					Speed:     60,
-					DayLength: 5,
+					YearLength: 40,
+					Latitude:   40,
 				},
 
 				Controls: struct {
@@ -41,6 +52,18 @@ func Test_validate(t *testing.T) {
 					MouseSensitivityY: 0.015,
 					MoveSpeed:         0.5,
 				},
+
+				Terrain: struct {
+					Generator Generator `json:"generator"`
+					Octaves   int       `json:"octaves"`
+					Roughness float32   `json:"roughness"`
+					Coastline float32   `json:"coastline"`
+				}{
+					Generator: Island,
+					Octaves:   4,
+					Roughness: 0.5,
+					Coastline: 0.5,
+				},
 			},
 		},
 		{
@@ -49,17 +72,18 @@ func Test_validate(t *testing.T) {
 				Name: "",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
 				}{
-					Width:     64,
-					Height:    64,
-					Depth:     64,
-					Speed:     60,
-					DayLength: 5,
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
 				},
 
 				Controls: struct {
@@ -80,17 +104,18 @@ func Test_validate(t *testing.T) {
 				Name: "Strands Test",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
 				}{
-					Width:     0,
-					Height:    64,
-					Depth:     64,
-					Speed:     60,
-					DayLength: 5,
+					Width:      0,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
 				},
 
 				Controls: struct {
@@ -111,17 +136,18 @@ func Test_validate(t *testing.T) {
 				Name: "Strands Test",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
 				}{
-					Width:     64,
-					Height:    0,
-					Depth:     64,
-					Speed:     60,
-					DayLength: 5,
+					Width:      64,
+					Height:     0,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
 				},
 
 				Controls: struct {
@@ -142,17 +168,18 @@ func Test_validate(t *testing.T) {
 				Name: "Strands Test",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
 				}{
-					Width:     64,
-					Height:    64,
-					Depth:     0,
-					Speed:     60,
-					DayLength: 5,
+					Width:      64,
+					Height:     64,
+					Depth:      0,
+					Speed:      60,
+					TickLength: 12,
 				},
 
 				Controls: struct {
@@ -173,16 +200,17 @@ func Test_validate(t *testing.T) {
 				Name: "Strands Test",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
 				}{
-					Width:     64,
-					Height:    64,
-					Depth:     64,
-					DayLength: 5,
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					TickLength: 12,
 				},
 
 				Controls: struct {
@@ -198,22 +226,23 @@ func Test_validate(t *testing.T) {
 			err: fmt.Errorf("%ssimulation speed (TPS) [0] too small", errInvalidCfg),
 		},
 		{
-			name: "Sad path - invalid day length",
+			name: "Sad path - invalid tick length",
 			cfg: Config{
 				Name: "Strands Test",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
 				}{
-					Width:     64,
-					Height:    64,
-					Depth:     64,
-					Speed:     60,
-					DayLength: 0,
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 0,
 				},
 
 				Controls: struct {
@@ -226,7 +255,37 @@ func Test_validate(t *testing.T) {
 					MoveSpeed:         0.5,
 				},
 			},
-			err: fmt.Errorf("%ssimulation day length too small: [0 minutes]", errInvalidCfg),
+			err: fmt.Errorf("%ssimulation tick length [0 s] must be between 1 and %d s", errInvalidCfg, MaxTickLength),
+		},
+		{
+			name: "Sad path - year too short for four seasons",
+			cfg: Config{
+				Name: "Strands Test",
+
+				Simulation: struct {
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
+				}{
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
+				},
+
+				Calendar: struct {
+					YearLength int     `json:"year_length_days"`
+					Latitude   float32 `json:"latitude"`
+				}{
+					YearLength: 3,
+					Latitude:   40,
+				},
+			},
+			err: fmt.Errorf("%syear length [%d days] too short for four seasons", errInvalidCfg, 3),
 		},
 		{
 			name: "Sad path - mouse X sensitivity too low",
@@ -234,17 +293,26 @@ func Test_validate(t *testing.T) {
 				Name: "Strands Test",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
 				}{
-					Width:     64,
-					Height:    64,
-					Depth:     64,
-					Speed:     60,
-					DayLength: 5,
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
+				},
+
+				Calendar: struct {
+					YearLength int     `json:"year_length_days"`
+					Latitude   float32 `json:"latitude"`
+				}{
+					YearLength: 40,
+					Latitude:   40,
 				},
 
 				Controls: struct {
@@ -265,17 +333,26 @@ func Test_validate(t *testing.T) {
 				Name: "Strands Test",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
+				}{
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
+				},
+
+				Calendar: struct {
+					YearLength int     `json:"year_length_days"`
+					Latitude   float32 `json:"latitude"`
 				}{
-					Width:     64,
-					Height:    64,
-					Depth:     64,
-					Speed:     60,
-					DayLength: 5,
+					YearLength: 40,
+					Latitude:   40,
 				},
 
 				Controls: struct {
@@ -296,17 +373,26 @@ func Test_validate(t *testing.T) {
 				Name: "Strands Test",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
 				}{
-					Width:     64,
-					Height:    64,
-					Depth:     64,
-					Speed:     60,
-					DayLength: 5,
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
+				},
+
+				Calendar: struct {
+					YearLength int     `json:"year_length_days"`
+					Latitude   float32 `json:"latitude"`
+				}{
+					YearLength: 40,
+					Latitude:   40,
 				},
 
 				Controls: struct {
@@ -327,17 +413,26 @@ func Test_validate(t *testing.T) {
 				Name: "Strands Test",
 
 				Simulation: struct {
-					Width     int `json:"-"`
-					Height    int `json:"-"`
-					Depth     int `json:"-"`
-					Speed     int `json:"ticks_per_second"`
-					DayLength int `json:"day_length_mins"`
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
+				}{
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
+				},
+
+				Calendar: struct {
+					YearLength int     `json:"year_length_days"`
+					Latitude   float32 `json:"latitude"`
 				}{
-					Width:     64,
-					Height:    64,
-					Depth:     64,
-					Speed:     60,
-					DayLength: 5,
+					YearLength: 40,
+					Latitude:   40,
 				},
 
 				Controls: struct {
@@ -352,6 +447,153 @@ func Test_validate(t *testing.T) {
 			},
 			err: fmt.Errorf("%smove speed must be between 0 and 1", errInvalidCfg),
 		},
+		{
+			name: "Sad path - prevailing wind too strong",
+			cfg: Config{
+				Name: "Strands Test",
+
+				Simulation: struct {
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
+				}{
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
+				},
+
+				Calendar: struct {
+					YearLength int     `json:"year_length_days"`
+					Latitude   float32 `json:"latitude"`
+				}{
+					YearLength: 40,
+					Latitude:   40,
+				},
+
+				Controls: struct {
+					MouseSensitivityX float32 `json:"mouse_sensitivity_x"`
+					MouseSensitivityY float32 `json:"mouse_sensitivity_y"`
+					MoveSpeed         float32 `json:"move_speed"`
+				}{
+					MouseSensitivityX: 0.025,
+					MouseSensitivityY: 0.015,
+					MoveSpeed:         0.5,
+				},
+
+				Weather: struct {
+					WindSpeed     float32 `json:"prevailing_wind_speed"`
+					WindDirection float32 `json:"prevailing_wind_direction"`
+				}{
+					WindSpeed: 25,
+				},
+			},
+			err: fmt.Errorf("%sprevailing wind speed must be between 0 and %d m/s", errInvalidCfg, MaxWindSpeed),
+		},
+		{
+			name: "Sad path - display units of the wrong dimension",
+			cfg: Config{
+				Name: "Strands Test",
+
+				Simulation: struct {
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
+				}{
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
+				},
+
+				Calendar: struct {
+					YearLength int     `json:"year_length_days"`
+					Latitude   float32 `json:"latitude"`
+				}{
+					YearLength: 40,
+					Latitude:   40,
+				},
+
+				Controls: struct {
+					MouseSensitivityX float32 `json:"mouse_sensitivity_x"`
+					MouseSensitivityY float32 `json:"mouse_sensitivity_y"`
+					MoveSpeed         float32 `json:"move_speed"`
+				}{
+					MouseSensitivityX: 0.025,
+					MouseSensitivityY: 0.015,
+					MoveSpeed:         0.5,
+				},
+
+				Units: struct {
+					Temperature chem.Unit `json:"temperature"`
+					Volume      chem.Unit `json:"volume"`
+					Mass        chem.Unit `json:"mass"`
+				}{
+					Volume: chem.Kilogram,
+				},
+			},
+			err: fmt.Errorf("%sdisplay units [%s] can't show %s", errInvalidCfg, chem.Kilogram, chem.Volume),
+		},
+		{
+			name: "Sad path - unknown terrain generator",
+			cfg: Config{
+				Name: "Strands Test",
+
+				Simulation: struct {
+					Width      int `json:"-"`
+					Height     int `json:"-"`
+					Depth      int `json:"-"`
+					Speed      int `json:"ticks_per_second"`
+					TickLength int `json:"tick_length_secs"`
+					DayLength  int `json:"day_length_mins,omitempty"`
+				}{
+					Width:      64,
+					Height:     64,
+					Depth:      64,
+					Speed:      60,
+					TickLength: 12,
+				},
+
+				Calendar: struct {
+					YearLength int     `json:"year_length_days"`
+					Latitude   float32 `json:"latitude"`
+				}{
+					YearLength: 40,
+					Latitude:   40,
+				},
+
+				Controls: struct {
+					MouseSensitivityX float32 `json:"mouse_sensitivity_x"`
+					MouseSensitivityY float32 `json:"mouse_sensitivity_y"`
+					MoveSpeed         float32 `json:"move_speed"`
+				}{
+					MouseSensitivityX: 0.025,
+					MouseSensitivityY: 0.015,
+					MoveSpeed:         0.5,
+				},
+
+				Terrain: struct {
+					Generator Generator `json:"generator"`
+					Octaves   int       `json:"octaves"`
+					Roughness float32   `json:"roughness"`
+					Coastline float32   `json:"coastline"`
+				}{
+					Generator: "volcano",
+					Octaves:   4,
+					Roughness: 0.5,
+					Coastline: 0.5,
+				},
+			},
+			err: fmt.Errorf("%sunknown terrain generator [%s]", errInvalidCfg, "volcano"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -5,17 +5,25 @@ var defaultConfig = &Config{
 	ShowHelp: false,
 	ExitSave: true,
 	Simulation: struct {
-		Width     int `json:"-"`
-		Height    int `json:"-"`
-		Depth     int `json:"-"`
-		Speed     int `json:"ticks_per_second"`
-		DayLength int `json:"day_length_mins"`
+		Width      int `json:"-"`
+		Height     int `json:"-"`
+		Depth      int `json:"-"`
+		Speed      int `json:"ticks_per_second"`
+		TickLength int `json:"tick_length_secs"`
+		DayLength  int `json:"day_length_mins,omitempty"`
 	}{
-		Width:     Width,
-		Height:    Height,
-		Depth:     Depth,
-		Speed:     24,
-		DayLength: 5,
+		Width:      Width,
+		Height:     Height,
+		Depth:      Depth,
+		Speed:      24,
+		TickLength: 1,
+	},
+	Calendar: struct {
+		YearLength int     `json:"year_length_days"`
+		Latitude   float32 `json:"latitude"`
+	}{
+		YearLength: 40,
+		Latitude:   40,
 	},
 	Controls: struct {
 		MouseSensitivityX float32 `json:"mouse_sensitivity_x"`
@@ -26,4 +34,29 @@ var defaultConfig = &Config{
 		MouseSensitivityY: 0.3,
 		MoveSpeed:         0.4,
 	},
+	Weather: struct {
+		WindSpeed     float32 `json:"prevailing_wind_speed"`
+		WindDirection float32 `json:"prevailing_wind_direction"`
+	}{
+		WindSpeed:     2,
+		WindDirection: 0,
+	},
+	Water: struct {
+		ShallowWater bool    `json:"shallow_water"`
+		SeaLevel     float32 `json:"sea_level"`
+	}{
+		ShallowWater: false,
+		SeaLevel:     0.7,
+	},
+	Terrain: struct {
+		Generator Generator `json:"generator"`
+		Octaves   int       `json:"octaves"`
+		Roughness float32   `json:"roughness"`
+		Coastline float32   `json:"coastline"`
+	}{
+		Generator: Island,
+		Octaves:   4,
+		Roughness: 0.5,
+		Coastline: 0.5,
+	},
 }
@@ -4,6 +4,6 @@ type Creature struct {
 }
 
 // Update a creature
-func (c *Creature) Update() {
+func (c *Creature) Update(seconds float32) {
 
 }
@@ -0,0 +1,164 @@
+package entity
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/g3n/engine/math32"
+)
+
+// Bounds is the range of values that a gene can take, and how far a mutation moves it
+type Bounds struct {
+	Min    float32
+	Max    float32
+	Spread float32 // The standard deviation of a mutation, as a fraction of the range
+}
+
+// The bounds of each gene, the colour's bounds apply to each of its red, green and blue channels
+var (
+	ColourBounds           = Bounds{Min: 0, Max: 0xff, Spread: 0.05}
+	NumLeavesBounds        = Bounds{Min: 1, Max: 12, Spread: 0.1}
+	LeafSpawnHeightBounds  = Bounds{Min: 0.1, Max: 1, Spread: 0.1}
+	AvgLeafSizeBounds      = Bounds{Min: 0.25, Max: 4, Spread: 0.05}
+	LeafSizeVarianceBounds = Bounds{Min: 0, Max: 1, Spread: 0.1}
+	DispersalRadiusBounds  = Bounds{Min: 1, Max: 4, Spread: 0.1}
+)
+
+var (
+	// The chance that each gene mutates when it's passed on
+	MutationRate float32 = 0.1
+
+	// How far a lineage's genes can drift from its species' founder before it branches off into a new species,
+	// counted in mutations of one spread
+	SpeciationDrift float32 = 10
+)
+
+// Genome is the heritable traits of a plant, which it passes on to its offspring
+type Genome struct {
+	Species          string  `json:"species"`            // The name of the species the plant belongs to
+	Drift            float32 `json:"drift"`              // How far the genes have drifted since the species was founded, in mutations of one spread
+	Colour           int     `json:"colour"`             // The colour of the plant as 0xRRGGBB
+	NumLeaves        int     `json:"num_leaves"`         // How many leaves the plant has. (More and bigger leaves consume more resources)
+	LeafSpawnHeight  float32 `json:"leaf_spawn_height"`  // How far up the stem the leaves will appear (from top), ie: value of 0.25 means leaves will spawn on the top quarter of the stem
+	AvgLeafSize      float32 `json:"avg_leaf_size"`      // Average size of leaf, ie: value of 1 equals the default size
+	LeafSizeVariance float32 `json:"leaf_size_variance"` // How much the leaf sizes can vary, ie: a value of 0.5 means the leaves can be up to 50% bigger or smaller than AvgSize
+	DispersalRadius  int     `json:"dispersal_radius"`   // How many tiles away from the plant its seeds can land
+}
+
+// The syllables that the names of new species are made up from
+var syllables = []string{"al", "bra", "cor", "den", "el", "fa", "gri", "hol", "is", "ju", "ka", "lo", "mi", "nar", "os", "pe", "ri", "sa", "tu", "ve"}
+
+// Make up a name for a new species
+func speciesName(rng *rand.Rand) string {
+	var name string
+	count := 2 + rng.Intn(2)
+	for i := 0; i < count; i++ {
+		name += syllables[rng.Intn(len(syllables))]
+	}
+
+	return strings.ToUpper(name[:1]) + name[1:] + "ia"
+}
+
+// Create a random genome for a plant with no parents, which founds a new species
+func RandomGenome(rng *rand.Rand) Genome {
+	return Genome{
+		Species: speciesName(rng),
+
+		// Random shade of green
+		Colour:    (int(0xdd+(2*rng.Float32()-1)*0x0f) << 8),
+		NumLeaves: rng.Intn(5) + 1,
+
+		// Hard-coded starting values for leaf data
+		LeafSpawnHeight:  0.5,
+		AvgLeafSize:      1,
+		LeafSizeVariance: 0.1,
+
+		DispersalRadius: rng.Intn(2) + 1,
+	}
+}
+
+// Mutate returns a copy of the genome where each gene has a chance of being nudged to a new value within its bounds.
+// The genes are visited in a fixed order, so the same random source always gives the same mutations.
+// Once the genes have drifted far enough from the species' founder, the genome founds a new species of its own.
+func (g Genome) Mutate(rng *rand.Rand) Genome {
+	channels := colourChannels(g.Colour)
+	for i := range channels {
+		channels[i] = mutate(rng, channels[i], ColourBounds, &g.Drift)
+	}
+
+	g.Colour = fromChannels(channels)
+	g.NumLeaves = mutate(rng, g.NumLeaves, NumLeavesBounds, &g.Drift)
+	g.LeafSpawnHeight = mutate(rng, g.LeafSpawnHeight, LeafSpawnHeightBounds, &g.Drift)
+	g.AvgLeafSize = mutate(rng, g.AvgLeafSize, AvgLeafSizeBounds, &g.Drift)
+	g.LeafSizeVariance = mutate(rng, g.LeafSizeVariance, LeafSizeVarianceBounds, &g.Drift)
+	g.DispersalRadius = mutate(rng, g.DispersalRadius, DispersalRadiusBounds, &g.Drift)
+
+	if g.Drift >= SpeciationDrift {
+		g.Species = speciesName(rng)
+		g.Drift = 0
+	}
+
+	return g
+}
+
+// Crossover returns a genome that takes each gene from one of two parents at random, the species of the first parent,
+// and the drift of whichever parent has drifted further
+func Crossover(a, b Genome, rng *rand.Rand) Genome {
+	channelsA, channelsB := colourChannels(a.Colour), colourChannels(b.Colour)
+
+	var channels [3]int
+	for i := range channels {
+		channels[i] = pick(rng, channelsA[i], channelsB[i])
+	}
+
+	return Genome{
+		Species:          a.Species,
+		Drift:            max(a.Drift, b.Drift),
+		Colour:           fromChannels(channels),
+		NumLeaves:        pick(rng, a.NumLeaves, b.NumLeaves),
+		LeafSpawnHeight:  pick(rng, a.LeafSpawnHeight, b.LeafSpawnHeight),
+		AvgLeafSize:      pick(rng, a.AvgLeafSize, b.AvgLeafSize),
+		LeafSizeVariance: pick(rng, a.LeafSizeVariance, b.LeafSizeVariance),
+		DispersalRadius:  pick(rng, a.DispersalRadius, b.DispersalRadius),
+	}
+}
+
+// Give a gene a chance of mutating, moving it by a random amount from a normal distribution and keeping it within its bounds.
+// The size of the mutation, in spreads, is added to the drift.
+func mutate[T int | float32](rng *rand.Rand, value T, bounds Bounds, drift *float32) T {
+	if rng.Float32() >= MutationRate {
+		return value
+	}
+
+	shift := float32(rng.NormFloat64())
+	*drift += math32.Abs(shift)
+
+	shifted := float32(value) + shift*bounds.Spread*(bounds.Max-bounds.Min)
+	shifted = math32.Max(bounds.Min, math32.Min(bounds.Max, shifted))
+
+	// Whole number genes round to the nearest whole number
+	if _, ok := any(value).(int); ok {
+		shifted = math32.Round(shifted)
+	}
+
+	return T(shifted)
+}
+
+// Pick one of two parents' genes at random
+func pick[T any](rng *rand.Rand, a, b T) T {
+	if rng.Intn(2) == 0 {
+		return a
+	}
+
+	return b
+}
+
+// Split a colour into its red, green and blue channels
+func colourChannels(colour int) [3]int {
+	return [3]int{colour >> 16 & 0xff, colour >> 8 & 0xff, colour & 0xff}
+}
+
+// Join red, green and blue channels into a colour
+func fromChannels(channels [3]int) int {
+	return channels[0]<<16 | channels[1]<<8 | channels[2]
+}
@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"encoding/json"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Mutate(t *testing.T) {
+	tests := []struct {
+		name      string
+		genome    Genome
+		rate      float32
+		changes   bool // Whether the mutated genome should differ from the original
+		speciates bool // Whether the mutated genome should found a new species
+	}{
+		{
+			name:    "Happy path - genes mutate",
+			genome:  Genome{Species: "Kaloria", Colour: 0x20dd20, NumLeaves: 3, LeafSpawnHeight: 0.5, AvgLeafSize: 1, LeafSizeVariance: 0.1, DispersalRadius: 2},
+			rate:    1,
+			changes: true,
+		},
+		{
+			name:    "Happy path - genes at their bounds stay within them",
+			genome:  Genome{Species: "Kaloria", Colour: 0xffffff, NumLeaves: 12, LeafSpawnHeight: 0.1, AvgLeafSize: 4, LeafSizeVariance: 0, DispersalRadius: 4},
+			rate:    1,
+			changes: true,
+		},
+		{
+			name:    "Sad path - nothing mutates without a chance to",
+			genome:  Genome{Species: "Kaloria", Colour: 0x20dd20, NumLeaves: 3, LeafSpawnHeight: 0.5, AvgLeafSize: 1, LeafSizeVariance: 0.1, DispersalRadius: 2},
+			rate:    0,
+			changes: false,
+		},
+		{
+			name:      "Happy path - a lineage that has drifted far enough founds a new species",
+			genome:    Genome{Species: "Kaloria", Drift: SpeciationDrift - 0.01, Colour: 0x20dd20, NumLeaves: 3, LeafSpawnHeight: 0.5, AvgLeafSize: 1, LeafSizeVariance: 0.1, DispersalRadius: 2},
+			rate:      1,
+			changes:   true,
+			speciates: true,
+		},
+		{
+			name:    "Sad path - a lineage that hasn't drifted far enough stays the same species",
+			genome:  Genome{Species: "Kaloria", Drift: 0, Colour: 0x20dd20, NumLeaves: 3, LeafSpawnHeight: 0.5, AvgLeafSize: 1, LeafSizeVariance: 0.1, DispersalRadius: 2},
+			rate:    1,
+			changes: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := MutationRate
+			MutationRate = tt.rate
+			defer func() { MutationRate = rate }()
+
+			mutated := tt.genome.Mutate(rand.New(rand.NewSource(1)))
+
+			// The same seed always gives the same mutations
+			assert.Equal(t, mutated, tt.genome.Mutate(rand.New(rand.NewSource(1))))
+			assert.Equal(t, tt.changes, mutated != tt.genome)
+			assert.Equal(t, tt.speciates, mutated.Species != tt.genome.Species)
+
+			// A new species starts drifting from its own founder
+			if tt.speciates {
+				assert.Zero(t, mutated.Drift)
+			} else {
+				assert.GreaterOrEqual(t, mutated.Drift, tt.genome.Drift)
+			}
+
+			for _, channel := range colourChannels(mutated.Colour) {
+				assert.True(t, float32(channel) >= ColourBounds.Min && float32(channel) <= ColourBounds.Max)
+			}
+			assert.True(t, float32(mutated.NumLeaves) >= NumLeavesBounds.Min && float32(mutated.NumLeaves) <= NumLeavesBounds.Max)
+			assert.True(t, mutated.LeafSpawnHeight >= LeafSpawnHeightBounds.Min && mutated.LeafSpawnHeight <= LeafSpawnHeightBounds.Max)
+			assert.True(t, mutated.AvgLeafSize >= AvgLeafSizeBounds.Min && mutated.AvgLeafSize <= AvgLeafSizeBounds.Max)
+			assert.True(t, mutated.LeafSizeVariance >= LeafSizeVarianceBounds.Min && mutated.LeafSizeVariance <= LeafSizeVarianceBounds.Max)
+			assert.True(t, float32(mutated.DispersalRadius) >= DispersalRadiusBounds.Min && float32(mutated.DispersalRadius) <= DispersalRadiusBounds.Max)
+		})
+	}
+}
+
+func Test_Crossover(t *testing.T) {
+	a := Genome{Species: "Kaloria", Drift: 2, Colour: 0x10c010, NumLeaves: 2, LeafSpawnHeight: 0.3, AvgLeafSize: 0.5, LeafSizeVariance: 0.2, DispersalRadius: 1}
+	b := Genome{Species: "Kaloria", Drift: 5, Colour: 0x30f030, NumLeaves: 8, LeafSpawnHeight: 0.9, AvgLeafSize: 2, LeafSizeVariance: 0.6, DispersalRadius: 3}
+	rng := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 20; i++ {
+		child := Crossover(a, b, rng)
+
+		// Every gene comes from one parent or the other
+		for c, channel := range colourChannels(child.Colour) {
+			assert.Contains(t, []int{colourChannels(a.Colour)[c], colourChannels(b.Colour)[c]}, channel)
+		}
+		assert.Contains(t, []int{a.NumLeaves, b.NumLeaves}, child.NumLeaves)
+		assert.Contains(t, []float32{a.LeafSpawnHeight, b.LeafSpawnHeight}, child.LeafSpawnHeight)
+		assert.Contains(t, []float32{a.AvgLeafSize, b.AvgLeafSize}, child.AvgLeafSize)
+		assert.Contains(t, []float32{a.LeafSizeVariance, b.LeafSizeVariance}, child.LeafSizeVariance)
+		assert.Contains(t, []int{a.DispersalRadius, b.DispersalRadius}, child.DispersalRadius)
+		assert.Equal(t, a.Species, child.Species)
+		assert.Equal(t, max(a.Drift, b.Drift), child.Drift)
+
+		// A plant's genome is saved with the rest of the plant
+		data, err := json.Marshal(NewSeedling(child, rng))
+		assert.NoError(t, err)
+
+		var plant Plant
+		assert.NoError(t, json.Unmarshal(data, &plant))
+		assert.Equal(t, child, plant.Genome)
+	}
+}
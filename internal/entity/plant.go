@@ -3,25 +3,20 @@ package entity
 import (
 	"fmt"
 	"math/rand"
-	"strconv"
 
-	"github.com/g3n/engine/core"
-	"github.com/g3n/engine/geometry"
-	"github.com/g3n/engine/graphic"
-	"github.com/g3n/engine/material"
 	"github.com/g3n/engine/math32"
-
-	"cbeimers113/strands/internal/graphics"
 )
 
 // The Plant entity
 type Plant struct {
-	*graphic.Mesh `json:"-"`
-	*rand.Rand    `json:"-"`
+	*rand.Rand `json:"-"`
+
+	// The plant's heritable traits, which are saved alongside the rest of the plant
+	Genome
 
 	// Whole Plant
-	Age    int     `json:"age"`
-	Colour int     `json:"colour"`
+	Age    float32 `json:"age_secs"`      // How many seconds of simulated time the plant has been alive
+	Ticks  int     `json:"age,omitempty"` // How many ticks old the plant was, only set in saves from before its age was counted in seconds
 	Height float32 `json:"height"`
 	Radius float32 `json:"radius"`
 	X      float32 `json:"x"`
@@ -29,128 +24,105 @@ type Plant struct {
 	RotX   float32 `json:"rot_x"`
 	RotY   float32 `json:"rot_y"`
 
-	// Leaves
-	NumLeaves        int     `json:"num_leaves"`         // (0, ..), How many leaves the plant has. (More and bigger leaves consume more resources)
-	LeafSpawnHeight  float32 `json:"leaf_spawn_height"`  // [0.1, 1], How far up the stem the leaves will appear (from top), ie: value of 0.25 means leaves will spawn on the top quarter of the stem
-	AvgLeafSize      float32 `json:"avg_leaf_size"`      // (0, ..), Average size of leaf, ie: value of 1 equals the default size
-	LeafSizeVariance float32 `json:"leaf_size_variance"` // [0, 1], How much the leaf sizes can vary, ie: a value of 0.5 means the leaves can be up to 50% bigger or smaller than AvgSize
-	Leaves           []*graphic.Mesh
+	// Condition
+	Biomass  float32 `json:"biomass"`  // The dry mass of the plant in kg, which it builds up by photosynthesis and loses in a fire
+	Moisture float32 `json:"moisture"` // [0, 1], How well watered the plant is, dry plants catch fire easily
+	Burning  bool    `json:"burning"`  // Whether the plant is on fire
 }
 
+// The dry mass in kg of a newly sprouted plant
+const SproutBiomass float32 = 0.001
+
+// The dry mass in kg of a plant whose stalk has grown to its full height
+const GrownBiomass float32 = 0.005
+
+// How many seconds of simulated time old a plant has to be to start producing seeds
+const MatureAge float32 = 120000
+
+// The area in m² of a leaf of the default size
+const UnitLeafArea float32 = 0.05
+
+// The colour of leaves that make the most of the sunlight falling on them
+const IdealGreen = 0x00ff00
+
 // Create a new plant
-func NewPlant(entities map[int]Entity, colour, numLeaves int, height, radius, x, z, rotX, rotY float32, rng *rand.Rand) *Plant {
+func NewPlant(genome Genome, height, radius, x, z, rotX, rotY float32, rng *rand.Rand) *Plant {
 	plant := &Plant{
-		Rand: rng,
-
-		Colour:    colour,
-		Height:    height,
-		Radius:    radius,
-		X:         x,
-		Z:         z,
-		RotX:      rotX,
-		RotY:      rotY,
-		NumLeaves: numLeaves,
-
-		// Hard-coded starting values for leaf data
-		LeafSpawnHeight:  0.5,
-		AvgLeafSize:      1,
-		LeafSizeVariance: 0.1,
+		Rand:   rng,
+		Genome: genome,
+
+		Height: height,
+		Radius: radius,
+		X:      x,
+		Z:      z,
+		RotX:   rotX,
+		RotY:   rotY,
+
+		Biomass:  SproutBiomass,
+		Moisture: 1,
 	}
 
 	return plant
 }
 
-// Create a new random plant
-func NewRandomPlant(entities map[int]Entity, rng *rand.Rand) *Plant {
-	// Random shade of green
-	colour := (int(0xdd+(2*rng.Float32()-1)*0x0f) << 8)
-	numLeaves := rng.Intn(5) + 1
+// Create a new plant with a given genome, sprouting at a random spot on its tile
+func NewSeedling(genome Genome, rng *rand.Rand) *Plant {
 	height := float32(1)
 	radius := float32(0.125)
 	x := rng.Float32()/4 - 1.0/8
 	z := rng.Float32()/4 - 1.0/8
 	rotX := math32.Pi * rng.Float32() / 4
 	rotY := 2 * math32.Pi * rng.Float32()
-	plant := NewPlant(entities, colour, numLeaves, height, radius, x, z, rotX, rotY, rng)
 
-	return plant
+	return NewPlant(genome, height, radius, x, z, rotX, rotY, rng)
 }
 
-// Create a new leaf
-func NewLeaf() (mesh *graphic.Mesh) {
-	geom := graphics.NewLeafMesh(2, 6, 2, 2)
-	mat := material.NewStandard(math32.NewColorHex(0x101010))
-	mesh = graphic.NewMesh(geom, mat)
-
-	if tex, err := graphics.Texture(graphics.TexGrass); err == nil {
-		mat.AddTexture(tex)
-	} else {
-		fmt.Println(err)
-	}
+// Create a new random plant
+func NewRandomPlant(rng *rand.Rand) *Plant {
+	return NewSeedling(RandomGenome(rng), rng)
+}
 
-	return
+// Perform per-tick updates to a plant over a number of seconds of simulated time
+func (p *Plant) Update(seconds float32) {
+	p.Age += seconds
 }
 
-// Refresh reloads the in-game configuration of the plant
-func (p *Plant) Refresh(entities map[int]Entity, scene *core.Node) {
-	geom := geometry.NewCylinder(float64(p.Radius), float64(p.Height), 8, 8, true, true)
-	mat := material.NewStandard(math32.NewColorHex(uint(p.Colour) / 10))
-	mesh := graphic.NewMesh(geom, mat)
-	mesh.SetScale(0.1, 0.1, 0.1)
-
-	if tex, err := graphics.Texture(graphics.TexStalk); err == nil {
-		mat.AddTexture(tex)
-	} else {
-		fmt.Println(err)
-	}
+// Growth returns how far the plant's stalk has grown, as a scale factor of its base height.
+// The stalk grows as the plant builds up mass, and shrinks back down as a fire burns it away.
+func (p Plant) Growth() float32 {
+	grown := (p.Biomass - SproutBiomass) / (GrownBiomass - SproutBiomass)
+	return 0.1 + 10*math32.Max(0, math32.Min(1, grown))
+}
 
-	if p.Mesh == nil {
-		p.Mesh = mesh
-		AddEntity(p, entities, scene)
-	} else {
-		// Make sure the entities map is pointing to this plant at the specified index
-		if i, err := strconv.Atoi(p.Name()); err == nil {
-			entities[i] = p
-		}
-	}
+// Mature returns whether the plant is old enough to produce seeds
+func (p Plant) Mature() bool {
+	return p.Age >= MatureAge
+}
 
-	p.SetPosition(p.X, mesh.Scale().Y, p.Z)
-	p.SetRotation(p.RotX, p.RotY, 0)
+// LeafArea returns the area in m² of the plant's leaves, which is how much sunlight it can catch
+func (p Plant) LeafArea() float32 {
+	return float32(p.NumLeaves) * p.AvgLeafSize * p.AvgLeafSize * UnitLeafArea
+}
 
-	// Make sure each leaf exists
-	if p.Leaves == nil || len(p.Leaves) != p.NumLeaves {
-		p.Leaves = make([]*graphic.Mesh, p.NumLeaves)
-	}
+// Greenness returns how efficiently the plant's leaves turn sunlight into mass, from 1 for the ideal green
+// down to 0 for the colour furthest from it
+func (p Plant) Greenness() float32 {
+	channels, ideal := colourChannels(p.Colour), colourChannels(IdealGreen)
 
-	for i := 0; i < p.NumLeaves; i++ {
-		if p.Leaves[i] == nil {
-			leaf := NewLeaf()
-			leaf.SetName(p.Name())
-			p.Add(leaf)
-			p.Leaves[i] = leaf
-		}
-
-		// TODO: store leaf positions and rotation in genetics and make reloadable
-		p.Leaves[i].SetScale(0.1, 0.1, 0.1)
-		p.Leaves[i].SetRotation(p.Rand.Float32()*math32.Pi/12, p.Rand.Float32()*2*math32.Pi, p.Rand.Float32()*math32.Pi/12)
+	var distance float32
+	for i := range channels {
+		distance += float32((channels[i] - ideal[i]) * (channels[i] - ideal[i]))
 	}
-}
 
-// Perform per-frame updates to a plant
-func (p *Plant) Update() {
-	p.Age++
-	scale := p.Scale()
-	scale.Y = 0.1 + (0.001 * math32.Min(float32(p.Age), 10000))
-	p.SetScale(scale.X, scale.Y, scale.Z)
-	p.SetPosition(p.X, 0.5+p.Scale().Y/2, p.Z)
+	return 1 - math32.Sqrt(distance)/math32.Sqrt(3*0xff*0xff)
 }
 
 // Infostring returns a string representation of the plant
 func (p Plant) InfoString() string {
-	return fmt.Sprintf("plant,  : %dt,  : #%06x", p.Age, p.Colour)
-}
+	info := fmt.Sprintf("%s,  : %.1fd,  : #%06x, 󰖌 : %.0f%%, 󰴭 : %.1f g", p.Species, p.Age/(24*60*60), p.Colour, 100*p.Moisture, 1000*p.Biomass)
+	if p.Burning {
+		info += ",  : burning"
+	}
 
-// Material returns the plant's material
-func (p Plant) Material() *material.Material {
-	return p.GetMaterial(0).GetMaterial()
+	return info
 }
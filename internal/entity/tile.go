@@ -3,33 +3,56 @@ package entity
 import (
 	"fmt"
 	"math/rand"
-	"strconv"
 
-	"github.com/g3n/engine/core"
-	"github.com/g3n/engine/graphic"
-	"github.com/g3n/engine/material"
 	"github.com/g3n/engine/math32"
 
 	"cbeimers113/strands/internal/chem"
-	"cbeimers113/strands/internal/graphics"
 )
 
+// The height of a tile's surface above its base, in metres
+const TileHeight float32 = 0.5
+
 type TileType struct {
-	Name      string
-	Fertility float32
+	Name         string     `json:"name"`
+	Fertility    float32    `json:"fertility"`
+	Texture      string     `json:"texture"`       // The name of the texture the tile is drawn with
+	HeatCapacity float32    `json:"heat_capacity"` // The energy in J it takes to warm the top layer of a square metre of the tile by 1°C
+	Permeability float32    `json:"permeability"`  // How fast standing water soaks into a square metre of the tile, in L/s
+	SoilCapacity float32    `json:"soil_capacity"` // The most water in L that the soil under the tile can hold
+	Erodibility  float32    `json:"erodibility"`   // How easily flowing water wears the tile away, relative to sand
+	SpawnBand    [2]float32 `json:"spawn_band"`    // The lowest and highest ground the tile can spawn on, from 0 for the lowest on the map to 1 for the highest
 }
 
-var Sand TileType = TileType{Name: "sand", Fertility: 0.05}
-var Dirt TileType = TileType{Name: "dirt", Fertility: 0.33}
-var Grass TileType = TileType{Name: "grass", Fertility: 0.80}
-var Stone TileType = TileType{Name: "stone", Fertility: 0.00}
+var Sand TileType = TileType{Name: "sand", Fertility: 0.05, Texture: "sand", HeatCapacity: 200_000, Permeability: 0.014, SoilCapacity: 350, Erodibility: 1, SpawnBand: [2]float32{0, 0.7}}
+var Dirt TileType = TileType{Name: "dirt", Fertility: 0.33, Texture: "dirt", HeatCapacity: 250_000, Permeability: 0.003, SoilCapacity: 400, Erodibility: 0.6, SpawnBand: [2]float32{0, 0.95}}
+var Grass TileType = TileType{Name: "grass", Fertility: 0.80, Texture: "grass", HeatCapacity: 280_000, Permeability: 0.004, SoilCapacity: 450, Erodibility: 0.3, SpawnBand: [2]float32{0.05, 0.8}}
+var Stone TileType = TileType{Name: "stone", Fertility: 0.00, Texture: "stone", HeatCapacity: 350_000, Permeability: 0, SoilCapacity: 0, Erodibility: 0.02, SpawnBand: [2]float32{0.8, 1}}
+var Mud TileType = TileType{Name: "mud", Fertility: 0.20, Texture: "mud", HeatCapacity: 320_000, Permeability: 0.001, SoilCapacity: 500, Erodibility: 0.8, SpawnBand: [2]float32{0, 0.4}}
+
+// How deep the loose ground over the bedrock of a newly spawned tile is, in metres
+const TopsoilDepth float32 = 0.25
+
+// How many seconds of simulated time a tile waits after spreading its water before it spreads it again
+var WaterSpreadInterval float32 = 120
 
-// Store list of tile types ordered by spawn height
+// Store list of tile types, these are the built in types unless they're replaced by LoadTileTypes
 var TileTypes []TileType = []TileType{
 	Sand,
 	Dirt,
 	Grass,
 	Stone,
+	Mud,
+}
+
+// Get the tile type with a given name, so that tiles loaded from a save pick up the current properties of their type
+func TileTypeNamed(name string) (TileType, bool) {
+	for _, tType := range TileTypes {
+		if tType.Name == name {
+			return tType, true
+		}
+	}
+
+	return TileType{}, false
 }
 
 // Represents the tiles surrounding this one
@@ -37,8 +60,7 @@ type Neighbourhood = [6]*Tile
 
 // The tile entity
 type Tile struct {
-	*graphic.Mesh `json:"-"`
-	*rand.Rand    `json:"-"`
+	*rand.Rand `json:"-"`
 
 	// Static properties
 	MapX   int      `json:"map_x"`
@@ -47,15 +69,23 @@ type Tile struct {
 	Type   TileType `json:"type"`
 
 	Neighbours Neighbourhood `json:"-"` // Pointers to any neighbouring tiles
-	Water      *graphic.Mesh `json:"-"` // This is the water tile object that can exist on top of the tile
 	WaterTick  int           `json:"water_tick"`
 
 	// Dynamic properties
-	Plants      []*Plant       `json:"plants"`
-	Temperature *chem.Quantity `json:"temperature"`
-	WaterLevel  *chem.Quantity `json:"water_level"`
-
-	physicsEnabled bool `json:"-"`
+	Plants       []*Plant       `json:"plants"`
+	Temperature  *chem.Quantity `json:"temperature"`
+	WaterLevel   *chem.Quantity `json:"water_level"`
+	SoilMoisture *chem.Quantity `json:"soil_moisture"` // The water held in the soil under the tile
+	Ice          *chem.Quantity `json:"ice"`           // The standing water on the tile that's frozen solid, which doesn't flow
+	Snow         *chem.Quantity `json:"snow"`          // The snow lying on the tile, measured as the water it melts into
+	Topsoil      *chem.Quantity `json:"topsoil"`       // How deep the loose ground over the tile's bedrock is, once it's worn away the bedrock shows through
+	Silt         float32        `json:"silt"`          // How deep a layer of sediment the water has laid down on the tile, in metres
+	Ash          float32        `json:"ash"`           // The mass of ash in kg left on the tile by fires and settled out of smoke, which feeds the soil
+	Sediment     float32        `json:"sediment"`      // The volume of sediment in m³ carried by the water on the tile
+	WaterFlux    [6]float32     `json:"water_flux"`    // The water flowing out of the tile towards each neighbour in L/s, only used by the shallow water model
+	Transition   float32        `json:"transition"`    // How many seconds the tile has met the conditions for turning into another type of tile
+	Flow         math32.Vector3 `json:"-"`             // The velocity of the water on the tile in m/s
+	Rain         float32        `json:"-"`             // Intensity of the rain falling on the tile in mm/h
 }
 
 // Spawn a hex tile of type tType at mapX, mapZ (tile precision), worldY (game world precision)
@@ -70,34 +100,42 @@ func NewTile(mapX, mapZ int, worldY, temp, waterLevel float32, tType TileType, r
 
 		WaterTick: rng.Intn(100),
 
-		Temperature: &chem.Quantity{Value: temp, Units: chem.Celcius},
-		WaterLevel:  &chem.Quantity{Value: waterLevel, Units: chem.Litre},
+		Temperature:  &chem.Quantity{Value: temp, Units: chem.Celcius},
+		WaterLevel:   &chem.Quantity{Value: waterLevel, Units: chem.Litre},
+		SoilMoisture: &chem.Quantity{Units: chem.Litre},
+		Ice:          &chem.Quantity{Units: chem.Litre},
+		Snow:         &chem.Quantity{Units: chem.Litre},
+		Topsoil:      NewTopsoil(tType),
 	}
 
 	return tile
 }
 
-// Create a base tile mesh with a given texture
-func createTileMesh(texture string) (tileMesh *graphic.Mesh) {
-	geom := graphics.NewHexMesh()
-	mat := material.NewStandard(math32.NewColorHex(0x111111))
-	mat.SetTransparent(texture == graphics.TexWater)
-	tileMesh = graphic.NewMesh(geom, mat)
-
-	if tex, err := graphics.Texture(texture); err == nil {
-		mat.AddTexture(tex)
-	} else {
-		fmt.Println(err)
+// NewTopsoil returns the topsoil that a newly spawned tile of a given type starts out with, stone tiles are bare bedrock
+func NewTopsoil(tType TileType) *chem.Quantity {
+	topsoil := &chem.Quantity{Units: chem.Metre}
+	if tType != Stone {
+		topsoil.Value = TopsoilDepth
 	}
 
-	return
+	return topsoil
+}
+
+// WorldX returns the x coordinate of the tile's centre in the game world
+func (t Tile) WorldX() float32 {
+	return (float32(t.MapX) + (0.5 * float32(t.MapZ%2))) * math32.Sin(math32.Pi/3)
+}
+
+// WorldZ returns the z coordinate of the tile's centre in the game world
+func (t Tile) WorldZ() float32 {
+	return float32(t.MapZ) * 0.75
 }
 
-// Get the elevation of the top of the tile, including its water
+// Get the elevation of the top of the tile, including its water and any ice that the water flows over
 func (t *Tile) getElevation() *chem.Quantity {
-	elevation := t.Position().Y
-	elevation += t.Water.Position().Y
-	elevation += chem.LitresToCubicMetres(t.WaterLevel.Value)
+	elevation := t.WorldY
+	elevation += TileHeight
+	elevation += chem.LitresToCubicMetres(t.WaterLevel.Value + t.Ice.Value)
 
 	return &chem.Quantity{
 		Value: elevation,
@@ -105,177 +143,125 @@ func (t *Tile) getElevation() *chem.Quantity {
 	}
 }
 
-// Spread water to other tiles
-func (t *Tile) doWaterSpread() {
-	t.WaterTick++
+// SpreadsWater returns whether the tile has water to spread and has waited long enough since it last spread it,
+// given how many seconds of simulated time each tick covers
+func (t Tile) SpreadsWater(tickSeconds float32) bool {
+	return float32(t.WaterTick)*tickSeconds > WaterSpreadInterval && t.WaterLevel.Value > 0
+}
 
-	if t.WaterTick > 10 && t.WaterLevel.Value > 0 {
-		var lowerNeighbours []*Tile
+// PlanWaterSpread works out how much water the tile spreads to each of its neighbours this tick, without moving any of it
+// or changing the tile. Every tile plans its spread from the same elevations before any water moves, so the flow doesn't
+// depend on the order that tiles are visited in and water can only move one tile per tick.
+func (t Tile) PlanWaterSpread(tickSeconds float32) (outflow [6]float32) {
+	if !t.SpreadsWater(tickSeconds) {
+		return
+	}
 
-		// Filter out the neighbouring tiles which aren't lower than this one
-		for _, neighbour := range t.Neighbours {
-			if neighbour == nil {
-				continue
-			}
+	elevation := t.getElevation().Value
 
-			if neighbour.getElevation().Value < t.getElevation().Value {
-				lowerNeighbours = append(lowerNeighbours, neighbour)
-			}
+	// Count the neighbouring tiles which are lower than this one
+	var lower int
+	for _, neighbour := range t.Neighbours {
+		if neighbour != nil && neighbour.getElevation().Value < elevation {
+			lower++
 		}
+	}
 
-		// Distribute water to lower neighbours
-		for len(lowerNeighbours) > 0 {
-			var neighbour *Tile
-
-			// Find lowest neighbour
-			for _, nb := range lowerNeighbours {
-				if neighbour == nil || nb.getElevation().Value < neighbour.getElevation().Value {
-					neighbour = nb
-				}
-			}
-
-			// Stop when this tile is already a local minimum
-			if neighbour.getElevation().Value >= t.getElevation().Value {
-				break
-			}
-
-			// Add d/n litres of water to the neighbour, where d is the elevation difference and n is the number of lower neighbours
-			delta := chem.CubicMetresToLitres(t.getElevation().Value-neighbour.getElevation().Value) / float32(len(lowerNeighbours))
-			neighbour.AddWater(delta - t.AddWater(-delta))
-
-			// Remove neighbour from lowerNeighbours
-			for i, nb := range lowerNeighbours {
-				if nb == neighbour {
-					lowerNeighbours[i] = lowerNeighbours[len(lowerNeighbours)-1]
-					lowerNeighbours = lowerNeighbours[:len(lowerNeighbours)-1]
-					break
-				}
-			}
-
-			// Stop when we run out of water to spread
-			if t.WaterLevel.Value == 0 {
-				break
-			}
+	// Give each lower neighbour d/(n+1) litres of water, where d is the elevation difference and n is the number of lower neighbours,
+	// so that a tile with a single lower neighbour evens out with it instead of pouring all the difference into it
+	var total float32
+	for i, neighbour := range t.Neighbours {
+		if neighbour != nil && neighbour.getElevation().Value < elevation {
+			outflow[i] = chem.CubicMetresToLitres(elevation-neighbour.getElevation().Value) / float32(lower+1)
+			total += outflow[i]
 		}
-
-		t.WaterTick = 0
 	}
-}
 
-// Update the tile's water level
-func (t *Tile) updateWaterLevel() {
-	waterLevel := t.WaterLevel.Value
-	water := t.Water
-
-	if water == nil {
-		return
+	// Never spread more water than the tile holds
+	if total > t.WaterLevel.Value {
+		for i := range outflow {
+			outflow[i] *= t.WaterLevel.Value / total
+		}
 	}
 
-	water.SetScaleY(chem.LitresToCubicMetres(waterLevel))
-	water.SetPositionY(graphics.DimensionsOf(water).Y)
-	water.SetVisible(t.WaterLevel.Value > 0)
+	return
+}
 
-	// Lower water texture opacity for low water level
-	if imat := water.GetMaterial(0); imat != nil {
-		if ms, ok := imat.(*material.Standard); ok {
-			ms.SetOpacity(math32.Min(50, waterLevel) / 60)
-		}
-	}
+// Perform per-tick updates to a Tile, which counts ticks since it last spread water whatever their length
+func (t *Tile) Update(seconds float32) {
+	t.WaterTick++
 }
 
-// Refresh the game object(s)
-func (t *Tile) Refresh(entities map[int]Entity, scene *core.Node) {
-	x := (float32(t.MapX) + (0.5 * float32(t.MapZ%2))) * math32.Sin(math32.Pi/3)
-	z := float32(t.MapZ) * 0.75
-
-	// Check if meshes have been created
-	if t.Mesh == nil {
-		t.Mesh = createTileMesh(t.Type.Name)
-		AddEntity(t, entities, scene)
-	} else {
-		if tex, err := graphics.Texture(t.Type.Name); tex != nil {
-			mat := t.GetMaterial(0).GetMaterial()
-
-			// Remove any existing textures before adding the one that matches the tile type
-			for texName := range graphics.Textures {
-				if oTex, err := graphics.Texture(texName); err == nil {
-					if mat.HasTexture(oTex) {
-						mat.RemoveTexture(oTex)
-					}
-				}
-			}
-
-			mat.AddTexture(tex)
-		} else {
-			fmt.Printf("Couldn't get tile texture for %s: %s\n", t.Type.Name, err)
-		}
+// Add an amount of water to a tile. Add a negative amount to remove water.
+// If the new volume is negative, return the amount that was lost
+func (t *Tile) AddWater(delta float32) float32 {
+	waterLevel := &t.WaterLevel.Value
+	backflow := -(*waterLevel + delta)
+	*waterLevel += delta
+	*waterLevel = math32.Max(0, *waterLevel)
 
-		// Make sure the entities map is pointing to this tile at the specified index
-		if i, err := strconv.Atoi(t.Name()); err == nil {
-			entities[i] = t
-		}
-	}
+	return math32.Max(backflow, 0)
+}
 
-	if t.Water == nil {
-		t.Water = createTileMesh(graphics.TexWater)
-		t.Water.SetName(t.Name())
-		t.Add(t.Water)
-		t.updateWaterLevel()
+// How much each kg of ash on a tile adds to its fertility
+const AshFertility float32 = 5
+
+// Fertility returns how well plants grow on the tile from 0 to 1, ash left by fires makes the ground more fertile
+func (t Tile) Fertility() float32 {
+	if t.Type.Fertility <= 0 {
+		return 0
 	}
 
-	t.SetPosition(x, t.WorldY, z)
-	t.SetRotationY(math32.Pi / 2)
-	t.GetMaterial(0).GetMaterial().SetLineWidth(8)
+	return min(1, t.Type.Fertility+AshFertility*t.Ash)
+}
 
-	// Refresh all plants
+// Burning returns whether any of the plants on the tile are on fire
+func (t Tile) Burning() bool {
 	for _, plant := range t.Plants {
-		plant.Rand = t.Rand
-		plant.Refresh(entities, scene)
-
-		has := false
-		for _, child := range t.Children() {
-			if child == plant.GetINode() {
-				has = true
-			}
-		}
-
-		if !has {
-			t.Add(plant)
+		if plant.Burning {
+			return true
 		}
 	}
+
+	return false
 }
 
-// Perform per-frame updates to a Tile
-func (t *Tile) Update() {
-	if t.physicsEnabled {
-		t.doWaterSpread()
-	}
+// The standing water, ice and snow in L on a tile that keeps the plants on it from burning
+var FireBreakWater float32 = 20
 
-	t.updateWaterLevel()
+// Flammability returns how readily the plants on the tile burn given the water, ice and snow on it,
+// from 1 on dry ground to 0 once it's a fire break
+func (t Tile) Flammability() float32 {
+	return max(0, 1-(t.WaterLevel.Value+t.Ice.Value+t.Snow.Value)/FireBreakWater)
 }
 
-// Pause or unpause physics
-func (t *Tile) PausePhysics(pause bool) {
-	t.physicsEnabled = !pause
+// Ignite sets every plant on the tile alight, returns whether there was anything on the tile that could burn.
+// Plants on a fire break would only be put out again, so they're left alone.
+func (t *Tile) Ignite() bool {
+	if t.Flammability() <= 0 {
+		return false
+	}
+
+	for _, plant := range t.Plants {
+		plant.Burning = true
+	}
+
+	return len(t.Plants) > 0
 }
 
-// Add an amount of water to a tile. Add a negative amount to remove water.
-// If the new volume is negative, return the amount that was lost
-func (t *Tile) AddWater(delta float32) float32 {
-	waterLevel := &t.WaterLevel.Value
-	backflow := -(*waterLevel + delta)
-	*waterLevel += delta
-	*waterLevel = math32.Max(0, *waterLevel)
+// Saturation returns how full of water the soil under the tile is, from 0 when it's dry to 1 when it can't hold any more
+func (t Tile) Saturation() float32 {
+	if t.Type.SoilCapacity <= 0 {
+		return 1
+	}
 
-	return math32.Max(backflow, 0)
+	return t.SoilMoisture.Value / t.Type.SoilCapacity
 }
 
-// Add a plant to a tile if plantable, returns whether it was planted
-func (t *Tile) AddPlant(entities map[int]Entity, scene *core.Node) bool {
+// Add a plant grown from a genome to a tile if plantable, returns whether it was planted
+func (t *Tile) AddPlant(genome Genome) bool {
 	if t.Type.Fertility > 0 {
-		plant := NewRandomPlant(entities, t.Rand)
-		plant.Refresh(entities, scene)
-		t.Add(plant)
+		plant := NewSeedling(genome, t.Rand)
 		t.Plants = append(t.Plants, plant)
 		return true
 	}
@@ -286,19 +272,39 @@ func (t *Tile) AddPlant(entities map[int]Entity, scene *core.Node) bool {
 // Infostring returns a string representation of the tile
 func (t Tile) InfoString() string {
 	return fmt.Sprintf(
-		"%s, : %s,  : %s,  : %s,  : %d",
+		"%s, : %s,  : %s, 󰖎 : %s,  : %s,  : %d",
 		t.Type.Name,
 		t.Temperature,
 		t.WaterLevel,
+		t.SoilMoisture,
 		t.getElevation(),
 		len(t.Plants),
-	)
+	) + t.flowString() + t.rainString() + t.frozenString()
 }
 
-// Material returns the tile's material
-func (t Tile) Material() *material.Material {
-	if mat0 := t.GetMaterial(0); mat0 != nil {
-		return mat0.GetMaterial()
+// Describe how fast the water on the tile is flowing, if it's moving at all
+func (t Tile) flowString() string {
+	if t.Flow.Length() < 0.01 {
+		return ""
 	}
-	return nil
+
+	return fmt.Sprintf(", 󰖌 : %.2f m/s", t.Flow.Length())
+}
+
+// Describe the rain falling on the tile, if there is any
+func (t Tile) rainString() string {
+	if t.Rain <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(",  : %.3f mm/h", t.Rain)
+}
+
+// Describe the ice and snow on the tile, if there's any
+func (t Tile) frozenString() string {
+	if t.Ice.Value <= 0 && t.Snow.Value <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(",  : %s ice, %s snow", t.Ice, t.Snow)
 }
@@ -0,0 +1,125 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/g3n/engine/math32"
+
+	"cbeimers113/strands/internal/graphics"
+	"cbeimers113/strands/internal/io/file"
+)
+
+const errInvalidTileTypes = "invalid tile types: "
+
+// The tile types that the simulation's own rules and transitions refer to, which every set of tile types has to define
+var requiredTileTypes = []*TileType{&Sand, &Dirt, &Grass, &Stone, &Mud}
+
+// LoadTileTypes replaces the built in tile types with the ones defined in tile_types.json under the storage path,
+// if the file exists.
+func LoadTileTypes() error {
+	path := filepath.Join(file.StoragePath, "tile_types.json")
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error reading tile types file: %w", err)
+	}
+
+	var tTypes []TileType
+	if err = json.Unmarshal(data, &tTypes); err != nil {
+		return fmt.Errorf("error unmarshaling tile types: %w", err)
+	}
+
+	if err = validateTileTypes(tTypes); err != nil {
+		return err
+	}
+
+	// Textures are checked here rather than when drawing the tiles, so that a typo doesn't quietly fall back to another texture
+	for _, tType := range tTypes {
+		if !graphics.HasTexture(tType.Texture) {
+			return fmt.Errorf("%s[%s] texture [%s] does not exist", errInvalidTileTypes, tType.Name, tType.Texture)
+		}
+	}
+
+	TileTypes = tTypes
+	for _, required := range requiredTileTypes {
+		*required, _ = TileTypeNamed(required.Name)
+	}
+
+	fmt.Printf("Loaded %d tile types from %s\n", len(tTypes), path)
+	return nil
+}
+
+// Check that every tile type in a set is usable, and that the set defines every tile type the simulation needs
+func validateTileTypes(tTypes []TileType) error {
+	names := make(map[string]bool)
+
+	for _, tType := range tTypes {
+		if tType.Name == "" {
+			return fmt.Errorf("%stile type name empty", errInvalidTileTypes)
+		}
+		if names[tType.Name] {
+			return fmt.Errorf("%stile type [%s] defined more than once", errInvalidTileTypes, tType.Name)
+		}
+		names[tType.Name] = true
+
+		if tType.Fertility < 0 || tType.Fertility > 1 {
+			return fmt.Errorf("%s[%s] fertility must be between 0 and 1", errInvalidTileTypes, tType.Name)
+		}
+		if tType.Texture == "" {
+			return fmt.Errorf("%s[%s] texture empty", errInvalidTileTypes, tType.Name)
+		}
+		if tType.HeatCapacity <= 0 {
+			return fmt.Errorf("%s[%s] heat capacity must be positive", errInvalidTileTypes, tType.Name)
+		}
+		if tType.Permeability < 0 || tType.SoilCapacity < 0 || tType.Erodibility < 0 {
+			return fmt.Errorf("%s[%s] permeability, soil capacity and erodibility can't be negative", errInvalidTileTypes, tType.Name)
+		}
+		if tType.SpawnBand[0] < 0 || tType.SpawnBand[0] > tType.SpawnBand[1] || tType.SpawnBand[1] > 1 {
+			return fmt.Errorf("%s[%s] spawn band must be a range between 0 and 1", errInvalidTileTypes, tType.Name)
+		}
+	}
+
+	for _, required := range requiredTileTypes {
+		if !names[required.Name] {
+			return fmt.Errorf("%smissing tile type [%s]", errInvalidTileTypes, required.Name)
+		}
+	}
+
+	return nil
+}
+
+// Spawns returns whether a tile of this type can spawn on ground at an elevation from 0 for the lowest on the map to 1 for the highest
+func (t TileType) Spawns(elevation float32) bool {
+	return t.SpawnBand[0] <= elevation && elevation <= t.SpawnBand[1]
+}
+
+// Choose the tile type for ground at an elevation from 0 for the lowest on the map to 1 for the highest, out of the types
+// that can spawn there. The preferred type is chosen if it can spawn there, otherwise the type closest to it in fertility.
+// Returns false if no type can spawn at the elevation.
+func ChooseTileType(elevation float32, preferred string) (TileType, bool) {
+	want, _ := TileTypeNamed(preferred)
+
+	var chosen TileType
+	var found bool
+	for _, tType := range TileTypes {
+		if !tType.Spawns(elevation) {
+			continue
+		}
+
+		if tType.Name == preferred {
+			return tType, true
+		}
+
+		if !found || math32.Abs(tType.Fertility-want.Fertility) < math32.Abs(chosen.Fertility-want.Fertility) {
+			chosen, found = tType, true
+		}
+	}
+
+	return chosen, found
+}
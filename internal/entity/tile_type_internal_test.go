@@ -0,0 +1,103 @@
+package entity
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateTileTypes(t *testing.T) {
+	clay := TileType{Name: "clay", Fertility: 0.4, Texture: "clay", HeatCapacity: 300_000, Permeability: 0.001, SoilCapacity: 500, Erodibility: 0.4, SpawnBand: [2]float32{0, 0.5}}
+
+	tests := []struct {
+		name   string
+		tTypes []TileType
+		err    error
+	}{
+		{
+			name:   "Happy path - built in tile types",
+			tTypes: TileTypes,
+		},
+		{
+			name:   "Happy path - an extra tile type",
+			tTypes: append([]TileType{clay}, TileTypes...),
+		},
+		{
+			name:   "Sad path - missing a tile type the simulation needs",
+			tTypes: []TileType{Sand, Dirt, Grass},
+			err:    fmt.Errorf("%smissing tile type [%s]", errInvalidTileTypes, Stone.Name),
+		},
+		{
+			name:   "Sad path - missing the tile type that soaked sand turns into",
+			tTypes: []TileType{Sand, Dirt, Grass, Stone},
+			err:    fmt.Errorf("%smissing tile type [%s]", errInvalidTileTypes, Mud.Name),
+		},
+		{
+			name:   "Sad path - tile type defined twice",
+			tTypes: append([]TileType{Sand}, TileTypes...),
+			err:    fmt.Errorf("%stile type [%s] defined more than once", errInvalidTileTypes, Sand.Name),
+		},
+		{
+			name:   "Sad path - spawn band out of order",
+			tTypes: append([]TileType{{Name: "clay", Texture: "clay", HeatCapacity: 1, SpawnBand: [2]float32{0.5, 0.2}}}, TileTypes...),
+			err:    fmt.Errorf("%s[clay] spawn band must be a range between 0 and 1", errInvalidTileTypes),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, validateTileTypes(tt.tTypes))
+		})
+	}
+}
+
+func Test_ChooseTileType(t *testing.T) {
+	tests := []struct {
+		name      string
+		elevation float32
+		preferred string
+		want      string
+		ok        bool
+	}{
+		{
+			name:      "Happy path - the preferred type spawns at the height",
+			elevation: 0.5,
+			preferred: Grass.Name,
+			want:      Grass.Name,
+			ok:        true,
+		},
+		{
+			name:      "Happy path - the type closest in fertility stands in for one that can't spawn at the height",
+			elevation: 0.02,
+			preferred: Grass.Name,
+			want:      "clay",
+			ok:        true,
+		},
+		{
+			name:      "Happy path - only one type spawns at the height",
+			elevation: 0.99,
+			preferred: Grass.Name,
+			want:      Stone.Name,
+			ok:        true,
+		},
+		{
+			name:      "Sad path - no type spawns at the height",
+			elevation: 2,
+			preferred: Grass.Name,
+			ok:        false,
+		},
+	}
+
+	tileTypes := TileTypes
+	TileTypes = append([]TileType{{Name: "clay", Fertility: 0.4, Texture: "clay", HeatCapacity: 300_000, SpawnBand: [2]float32{0, 0.5}}}, TileTypes...)
+	defer func() { TileTypes = tileTypes }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ChooseTileType(tt.elevation, tt.preferred)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got.Name)
+		})
+	}
+}
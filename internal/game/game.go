@@ -23,6 +23,7 @@ import (
 	"cbeimers113/strands/internal/io/input_manager"
 	"cbeimers113/strands/internal/io/keyboard"
 	"cbeimers113/strands/internal/player"
+	"cbeimers113/strands/internal/sim"
 	"cbeimers113/strands/internal/state"
 	"cbeimers113/strands/internal/world"
 )
@@ -32,6 +33,7 @@ type Game struct {
 
 	gui    *gui.Gui
 	iman   *input_manager.InputManager
+	sim    *sim.Simulation
 	world  *world.World
 	player *player.Player
 
@@ -57,10 +59,12 @@ func New(cfg *config.Config, version string) (*Game, error) {
 	ctx.Notifications = context.NewNotificationManager(func() *core.Node { return ctx.Scene }, ctx.App)
 	graphics.LoadTextures()
 
+	s := sim.New(cfg, ctx.State)
 	g := &Game{
 		Context: ctx,
 		gui:     gui.New(ctx),
-		world:   world.New(ctx),
+		sim:     s,
+		world:   world.New(ctx, s),
 		player:  player.New(ctx),
 		iman:    input_manager.New(ctx),
 	}
@@ -103,7 +107,8 @@ func (g *Game) CreateNewSim() {
 
 	g.State = state.New(g.Cfg, time.Now().UnixNano())
 	g.gui = gui.New(g.Context)
-	g.world = world.New(g.Context)
+	g.sim = sim.New(g.Cfg, g.State)
+	g.world = world.New(g.Context, g.sim)
 	g.player = player.New(g.Context)
 
 	g.Cam.SetPosition(float32(g.Cfg.Simulation.Width)/2, 10, float32(g.Cfg.Simulation.Depth)/2)
@@ -130,7 +135,8 @@ func (g *Game) LoadGame(filename string) {
 		// Build new state
 		g.State = st
 		g.gui = gui.New(g.Context)
-		g.world = world.Load(g.Context, tiles, cells)
+		g.sim = sim.Load(g.Cfg, st, tiles, cells)
+		g.world = world.New(g.Context, g.sim)
 		g.Cam.SetPosition(camData.PosX, camData.PosY, camData.PosZ)
 		g.Cam.SetRotation(camData.RotX, camData.RotY, 0)
 		g.CurrentSave = filename
@@ -150,13 +156,22 @@ func (g *Game) LoadGame(filename string) {
 // Save a save file
 func (g Game) SaveGame(filename string) {
 	g.Win.SetTitle(fmt.Sprintf("Saving Simulation [%s]", filename))
+	pos := g.Cam.Position()
+	rot := g.Cam.Rotation()
+
 	if err := state.StoreSave(
 		filename,
 		g.State,
-		g.world.GetAtmosphere(),
-		g.world.GetTiles(),
-		g.Cam.Position(),
-		g.Cam.Rotation(),
+		g.sim.GetAtmosphere(),
+		g.sim.GetTiles(),
+		state.CamData{
+			PosX: pos.X,
+			PosY: pos.Y,
+			PosZ: pos.Z,
+
+			RotX: rot.X,
+			RotY: rot.Y,
+		},
 	); err != nil {
 		msg := fmt.Sprintf("Couldn't create save file [%s]: %s", filename, err)
 		fmt.Println(msg)
@@ -191,7 +206,7 @@ func (g *Game) Start() {
 		g.Notifications.Render()
 
 		// Determine player max bounds based on map and tile size
-		maxPos := g.world.GetTile(g.Cfg.Simulation.Width-1, g.Cfg.Simulation.Depth-1).Position()
+		maxPos := g.world.TilePosition(g.Cfg.Simulation.Width-1, g.Cfg.Simulation.Depth-1)
 		maxPlayerX := maxPos.X
 		maxPlayerZ := maxPos.Z
 
@@ -206,7 +221,7 @@ func (g *Game) Start() {
 				if !g.camSpin {
 					p := g.Cam.Position()
 					g.camSpin = true
-					g.centre = g.world.GetTile(g.Cfg.Simulation.Width/2, g.Cfg.Simulation.Depth/2).Position()
+					g.centre = g.world.TilePosition(g.Cfg.Simulation.Width/2, g.Cfg.Simulation.Depth/2)
 					g.camDist = math32.Sqrt(
 						(p.X-g.centre.X)*(p.X-g.centre.X) +
 							(p.Y-g.centre.Y)*(p.Y-g.centre.Y) +
@@ -248,14 +263,20 @@ func (g *Game) Start() {
 				deltaTimeController = 0
 			}
 
-			// Update the world at a dynamic configurable rate
+			// Update the simulation at a dynamic configurable rate and redraw the world to match it,
+			// each tick covers the same amount of simulated time however often it runs
 			if deltaTimeWorld >= 1000/float32(g.Cfg.Simulation.Speed) {
-				g.world.Update(deltaTimeWorld)
+				ticked := !g.State.Paused()
+				if ticked {
+					g.sim.Update()
 
-				if !g.State.Paused() {
-					g.State.Clock.Update(deltaTimeWorld)
+					for _, message := range g.sim.Events() {
+						g.Notifications.Push(message)
+					}
 				}
 
+				g.world.Update(ticked)
+
 				deltaTimeWorld = 0
 				tps++
 			}
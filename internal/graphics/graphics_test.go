@@ -39,3 +39,28 @@ func Test_Texture(t *testing.T) {
 		})
 	}
 }
+
+func Test_HasTexture(t *testing.T) {
+	tests := []struct {
+		name  string
+		texId string
+		want  bool
+	}{
+		{
+			name:  "Happy path - built in texture",
+			texId: TexMud,
+			want:  true,
+		},
+		{
+			name:  "Sad path - texture doesn't exist",
+			texId: "no_tex",
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HasTexture(tt.texId))
+		})
+	}
+}
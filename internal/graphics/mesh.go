@@ -1,6 +1,8 @@
 package graphics
 
 import (
+	"math/rand"
+
 	"github.com/g3n/engine/geometry"
 	"github.com/g3n/engine/gls"
 	"github.com/g3n/engine/graphic"
@@ -149,6 +151,39 @@ func NewLeafMesh(w, l, s, c float32) (geom *geometry.Geometry) {
 	return
 }
 
+// Generate a number of rain streaks scattered through a hexagonal column of a given height, to be drawn as lines
+func NewRainGeometry(streaks int, height float32, rng *rand.Rand) (geom *geometry.Geometry) {
+	geom = geometry.NewGeometry()
+	vertices := math32.NewArrayF32(0, streaks*6)
+	colours := math32.NewArrayF32(0, streaks*6)
+
+	// Each streak is a short vertical line at a random point within the hexagon's inner circle
+	r := float32(0.5) * math32.Sin(math32.Pi/3)
+	length := float32(0.3)
+
+	for i := 0; i < streaks; i++ {
+		d := r * math32.Sqrt(rng.Float32())
+		a := 2 * math32.Pi * rng.Float32()
+		x := d * math32.Cos(a)
+		z := d * math32.Sin(a)
+		y := rng.Float32() * (height - length)
+
+		vertices.Append(
+			x, y, z, // bottom of the streak
+			x, y+length, z, // top of the streak
+		)
+		colours.Append(
+			0.6, 0.7, 0.9,
+			0.8, 0.85, 0.95,
+		)
+	}
+
+	geom.AddVBO(gls.NewVBO(vertices).AddAttrib(gls.VertexPosition))
+	geom.AddVBO(gls.NewVBO(colours).AddAttrib(gls.VertexColor))
+
+	return
+}
+
 // Get the dimensions of a mesh
 func DimensionsOf(mesh *graphic.Mesh) *math32.Vector3 {
 	bb := mesh.BoundingBox()
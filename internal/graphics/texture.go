@@ -7,8 +7,12 @@ import (
 	"fmt"
 	"image"
 	"image/draw"
+	"os"
+	"path/filepath"
 
 	"github.com/g3n/engine/texture"
+
+	"cbeimers113/strands/internal/io/file"
 )
 
 const (
@@ -23,8 +27,11 @@ const (
 	TexHorizon   = "horizon"
 	TexDirt      = "dirt"
 	TexGrass     = "grass"
+	TexIce       = "ice"
+	TexMud       = "mud"
 	TexSand      = "sand"
 	TexSeed      = "seed"
+	TexSnow      = "snow"
 	TexStalk     = "stalk"
 	TexStone     = "stone"
 	TexWater     = "water"
@@ -48,10 +55,16 @@ var (
 	bytesDirt []byte
 	//go:embed textures/world/grass.png
 	bytesGrass []byte
+	//go:embed textures/world/ice.png
+	bytesIce []byte
+	//go:embed textures/world/mud.png
+	bytesMud []byte
 	//go:embed textures/world/sand.png
 	bytesSand []byte
 	//go:embed textures/world/seed.png
 	bytesSeed []byte
+	//go:embed textures/world/snow.png
+	bytesSnow []byte
 	//go:embed textures/world/stalk.png
 	bytesStalk []byte
 	//go:embed textures/world/stone.png
@@ -62,28 +75,34 @@ var (
 
 var Textures map[string]*texture.Texture2D
 
+// The textures that are built into the game
+var builtinTextures = []struct {
+	id   string
+	data []byte
+}{
+	{id: TexMenuLogo, data: bytesMenuLogo},
+	{id: TexCursor, data: bytesCursor},
+
+	{id: TexHighlight, data: bytesHighlight},
+	{id: TexSky, data: bytesSky},
+	{id: TexStars, data: bytesStars},
+	{id: TexHorizon, data: bytesHorizon},
+	{id: TexDirt, data: bytesDirt},
+	{id: TexGrass, data: bytesGrass},
+	{id: TexIce, data: bytesIce},
+	{id: TexMud, data: bytesMud},
+	{id: TexSand, data: bytesSand},
+	{id: TexSeed, data: bytesSeed},
+	{id: TexSnow, data: bytesSnow},
+	{id: TexStalk, data: bytesStalk},
+	{id: TexStone, data: bytesStone},
+	{id: TexWater, data: bytesWater},
+}
+
 func LoadTextures() {
 	Textures = make(map[string]*texture.Texture2D)
 
-	for _, texLoader := range []struct {
-		id   string
-		data []byte
-	}{
-		{id: TexMenuLogo, data: bytesMenuLogo},
-		{id: TexCursor, data: bytesCursor},
-
-		{id: TexHighlight, data: bytesHighlight},
-		{id: TexSky, data: bytesSky},
-		{id: TexStars, data: bytesStars},
-		{id: TexHorizon, data: bytesHorizon},
-		{id: TexDirt, data: bytesDirt},
-		{id: TexGrass, data: bytesGrass},
-		{id: TexSand, data: bytesSand},
-		{id: TexSeed, data: bytesSeed},
-		{id: TexStalk, data: bytesStalk},
-		{id: TexStone, data: bytesStone},
-		{id: TexWater, data: bytesWater},
-	} {
+	for _, texLoader := range builtinTextures {
 		var (
 			tex *texture.Texture2D
 			err error
@@ -114,12 +133,45 @@ func decode(data []byte) (tex *texture.Texture2D, err error) {
 	return
 }
 
-// Texture returns the texture for a given key if it exists, and errors if it doesn't
+// Texture returns the texture for a given key if it exists, and errors if it doesn't.
+// Textures that aren't built in are loaded from a png of the same name in the textures folder under the storage path.
 func Texture(texId string) (tex *texture.Texture2D, err error) {
 	var ok bool
-	if tex, ok = Textures[texId]; !ok {
-		err = fmt.Errorf("texture does not exist: [%s]", texId)
+	if tex, ok = Textures[texId]; ok {
+		return
 	}
 
+	path := storedTexture(texId)
+	if !file.Exists(path) {
+		return nil, fmt.Errorf("texture does not exist: [%s]", texId)
+	}
+
+	var data []byte
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, fmt.Errorf("error reading texture [%s]: %w", texId, err)
+	}
+
+	if tex, err = decode(data); err != nil {
+		return nil, fmt.Errorf("error loading texture [%s]: %w", texId, err)
+	}
+
+	Textures[texId] = tex
 	return
 }
+
+// HasTexture returns whether a texture is built in or there's a png of the same name in the textures folder under the storage path,
+// without loading it
+func HasTexture(texId string) bool {
+	for _, builtin := range builtinTextures {
+		if builtin.id == texId {
+			return true
+		}
+	}
+
+	return file.Exists(storedTexture(texId))
+}
+
+// Get the path that a texture that isn't built in is loaded from
+func storedTexture(texId string) string {
+	return filepath.Join(file.StoragePath, "textures", texId+".png")
+}
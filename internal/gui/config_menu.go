@@ -24,7 +24,7 @@ func (g *Gui) registerConfigMenu() {
 				saveMouseSensX float32 = g.Cfg.Controls.MouseSensitivityX
 				saveMouseSensY float32 = g.Cfg.Controls.MouseSensitivityY
 				saveTickSpeed  int     = g.Cfg.Simulation.Speed
-				saveDayLength  int     = g.Cfg.Simulation.DayLength
+				saveTickLength int     = g.Cfg.Simulation.TickLength
 			)
 
 			g.showControlsCheck = gui.NewCheckBox("Show Controls")
@@ -112,19 +112,18 @@ func (g *Gui) registerConfigMenu() {
 			g.Scene.Add(g.tickSpeedSlider)
 			nextY = g.tickSpeedSlider.Position().Y + g.tickSpeedSlider.Height() + 5
 
-			g.dayLengthSlider = gui.NewHSlider(350, 12.5)
-			w = g.dayLengthSlider.Width()
-			g.dayLengthSlider.SetPosition((float32(width)-w)/2, nextY)
-			g.dayLengthSlider.SetUserData(ConfigMenu)
-			g.dayLengthSlider.SetValue(float32(g.Cfg.Simulation.DayLength+1) / 30)
-			g.dayLengthSlider.SetText(g.dayLengthLabel())
-			g.dayLengthSlider.Subscribe(gui.OnChange, func(name string, ev interface{}) {
-				g.Cfg.Simulation.DayLength = int(math32.Max(1, g.dayLengthSlider.Value()*30))
-				g.State.Clock.SetTime(g.State.Clock.Hour, g.State.Clock.Minute)
-				g.dayLengthSlider.SetText(g.dayLengthLabel())
+			g.tickLengthSlider = gui.NewHSlider(350, 12.5)
+			w = g.tickLengthSlider.Width()
+			g.tickLengthSlider.SetPosition((float32(width)-w)/2, nextY)
+			g.tickLengthSlider.SetUserData(ConfigMenu)
+			g.tickLengthSlider.SetValue(float32(g.Cfg.Simulation.TickLength) / 60)
+			g.tickLengthSlider.SetText(g.tickLengthLabel())
+			g.tickLengthSlider.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+				g.Cfg.Simulation.TickLength = int(math32.Max(1, g.tickLengthSlider.Value()*60))
+				g.tickLengthSlider.SetText(g.tickLengthLabel())
 			})
-			g.Scene.Add(g.dayLengthSlider)
-			nextY = g.dayLengthSlider.Position().Y + g.dayLengthSlider.Height() + 10
+			g.Scene.Add(g.tickLengthSlider)
+			nextY = g.tickLengthSlider.Position().Y + g.tickLengthSlider.Height() + 10
 
 			g.saveButton = gui.NewButton("Save Settings")
 			w = g.saveButton.Width()
@@ -160,7 +159,7 @@ func (g *Gui) registerConfigMenu() {
 				g.Cfg.Controls.MouseSensitivityX = saveMouseSensX
 				g.Cfg.Controls.MouseSensitivityY = saveMouseSensY
 				g.Cfg.Simulation.Speed = saveTickSpeed
-				g.Cfg.Simulation.DayLength = saveDayLength
+				g.Cfg.Simulation.TickLength = saveTickLength
 				Open(MainMenu, true)
 			})
 			g.exitButton.Subscribe(gui.OnCursor, func(s string, i interface{}) {
@@ -184,7 +183,7 @@ func (g *Gui) registerConfigMenu() {
 			g.Scene.Remove(g.mouseYSenSlider)
 			g.Scene.Remove(g.moveSpeedSlider)
 			g.Scene.Remove(g.tickSpeedSlider)
-			g.Scene.Remove(g.dayLengthSlider)
+			g.Scene.Remove(g.tickLengthSlider)
 			g.Scene.Remove(g.saveButton)
 			g.Scene.Remove(g.exitButton)
 		},
@@ -209,13 +208,13 @@ func (g *Gui) tickSpeedLabel() string {
 	return fmt.Sprintf("Target Sim Speed: %d t/s", g.Cfg.Simulation.Speed)
 }
 
-func (g *Gui) dayLengthLabel() string {
+func (g *Gui) tickLengthLabel() string {
 	suffix := "s"
-	d := g.Cfg.Simulation.DayLength
+	t := g.Cfg.Simulation.TickLength
 
-	if d == 1 {
+	if t == 1 {
 		suffix = ""
 	}
 
-	return fmt.Sprintf("Day Length: %d min%s", d, suffix)
+	return fmt.Sprintf("Tick Length: %d sim second%s", t, suffix)
 }
@@ -61,6 +61,9 @@ type Gui struct {
 	cancelButton   *gui.Button
 	exitButton     *gui.Button
 
+	// New simulation popup components
+	generatorDropDown *gui.DropDown
+
 	// Config menu components
 	showControlsCheck *gui.CheckRadio
 	exitSaveCheck     *gui.CheckRadio
@@ -69,7 +72,7 @@ type Gui struct {
 	mouseYSenSlider   *gui.Slider
 	moveSpeedSlider   *gui.Slider
 	tickSpeedSlider   *gui.Slider
-	dayLengthSlider   *gui.Slider
+	tickLengthSlider  *gui.Slider
 	saveButton        *gui.Button
 
 	// Simulation view components
@@ -84,6 +87,7 @@ type Gui struct {
 	// Tile context menu components
 	tileInfoLabel   *gui.Label
 	plantSeedButton *gui.Button
+	startFireButton *gui.Button
 
 	// GUI flags
 	gameStarted bool
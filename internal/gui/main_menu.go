@@ -8,6 +8,7 @@ import (
 	"github.com/g3n/engine/math32"
 	"github.com/g3n/engine/texture"
 
+	"cbeimers113/strands/internal/config"
 	"cbeimers113/strands/internal/graphics"
 	"cbeimers113/strands/internal/gui/color"
 	"cbeimers113/strands/internal/gui/component"
@@ -321,7 +322,7 @@ func (g *Gui) closeBrowseDialog() {
 func (g *Gui) openConfirmNewPopup() {
 	width, height := g.App.GetSize()
 	w := float32(150)
-	h := float32(100)
+	h := float32(160)
 	x := (float32(width) - w) / 2
 	y := (float32(height) - h) / 2
 
@@ -359,6 +360,22 @@ func (g *Gui) openConfirmNewPopup() {
 	g.popup.SetUserData(MainMenu)
 	g.Scene.Add(g.popup)
 	g.popup.Open(float32(width), float32(height))
+
+	// Let the player pick how the new simulation's terrain is generated
+	g.generatorDropDown = gui.NewDropDown(120, gui.NewImageLabel(""))
+	for i, generator := range config.Generators {
+		g.generatorDropDown.Add(gui.NewImageLabel(fmt.Sprintf("Terrain: %s", generator)))
+
+		if generator == g.Cfg.Terrain.Generator {
+			g.generatorDropDown.SelectPos(i)
+		}
+	}
+	g.generatorDropDown.SetPosition((g.popup.Width()-g.generatorDropDown.Width())/2, 45)
+	g.generatorDropDown.SetUserData(MainMenu)
+	g.generatorDropDown.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		g.Cfg.Terrain.Generator = config.Generators[g.generatorDropDown.SelectedPos()]
+	})
+	g.popup.Add(g.generatorDropDown)
 }
 
 func (g *Gui) openConfirmOpenPopup() {
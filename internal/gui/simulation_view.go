@@ -7,6 +7,7 @@ import (
 	"github.com/g3n/engine/gui"
 	"github.com/g3n/engine/texture"
 
+	"cbeimers113/strands/internal/chem"
 	"cbeimers113/strands/internal/graphics"
 	"cbeimers113/strands/internal/gui/color"
 )
@@ -162,8 +163,20 @@ func helpText() string {
 // Get chemical quantities
 func (g *Gui) getQuantities() string {
 	txt := "Chemical Levels:\n"
-	for name, amnt := range g.State.Quantities {
-		txt += fmt.Sprintf("%s: %s\n", name, amnt.String())
+	for _, name := range g.State.QuantityNames() {
+		label := name
+		if element, ok := chem.ElementOf(name); ok {
+			label = fmt.Sprintf("%s (%s)", name, element.Formula)
+		}
+
+		txt += fmt.Sprintf("%s: %s\n", label, g.State.Quantities[name])
+	}
+
+	if len(g.State.Births) > 0 {
+		txt += "\nPlant Births:\n"
+		for _, species := range g.State.SpeciesNames() {
+			txt += fmt.Sprintf("%s: %d\n", species, g.State.Births[species])
+		}
 	}
 
 	return strings.TrimSpace(txt)
@@ -43,7 +43,7 @@ func (g *Gui) registerTileContextMenu() {
 			g.plantSeedButton.SetUserData(TileContextMenu)
 			g.plantSeedButton.Subscribe(gui.OnClick, func(name string, ev interface{}) {
 				if tile, ok := g.State.LookingAt.(*entity.Tile); ok {
-					planted := tile.AddPlant(g.State.Entities, g.Scene)
+					planted := tile.AddPlant(entity.RandomGenome(tile.Rand))
 					g.tileInfoLabel.SetText(g.State.LookingAt.InfoString())
 
 					if planted {
@@ -56,6 +56,27 @@ func (g *Gui) registerTileContextMenu() {
 			g.Scene.Add(g.plantSeedButton)
 			nextY = g.plantSeedButton.Position().Y + g.plantSeedButton.Height() + 5
 
+			g.startFireButton = gui.NewButton("Start Fire")
+			w = g.startFireButton.Width()
+			g.startFireButton.SetPosition((float32(width)-w)/2, nextY)
+			g.startFireButton.SetUserData(TileContextMenu)
+			g.startFireButton.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+				if tile, ok := g.State.LookingAt.(*entity.Tile); ok {
+					ignited := tile.Ignite()
+					g.tileInfoLabel.SetText(g.State.LookingAt.InfoString())
+
+					if ignited {
+						g.Notifications.Push(fmt.Sprintf("Fire started at (%d, %d)", tile.MapX, tile.MapZ))
+					} else if len(tile.Plants) > 0 {
+						g.Notifications.Push(fmt.Sprintf("Plants on %s tile at (%d, %d) are too %s to burn", tile.Type.Name, tile.MapX, tile.MapZ, fireBreak(tile)))
+					} else {
+						g.Notifications.Push(fmt.Sprintf("Nothing to burn on %s tile at (%d, %d)", tile.Type.Name, tile.MapX, tile.MapZ))
+					}
+				}
+			})
+			g.Scene.Add(g.startFireButton)
+			nextY = g.startFireButton.Position().Y + g.startFireButton.Height() + 5
+
 			g.exitButton = gui.NewButton("Close")
 			w = g.exitButton.Width()
 			g.exitButton.SetPosition((float32(width)-w)/2, nextY)
@@ -71,9 +92,22 @@ func (g *Gui) registerTileContextMenu() {
 		close: func() {
 			g.Scene.Remove(g.tileInfoLabel)
 			g.Scene.Remove(g.plantSeedButton)
+			g.Scene.Remove(g.startFireButton)
 			g.Scene.Remove(g.exitButton)
 		},
 
 		refresh: func() {},
 	}
 }
+
+// Describe what's keeping a tile from burning, whichever of its water, ice and snow there's the most of
+func fireBreak(tile *entity.Tile) string {
+	switch {
+	case tile.WaterLevel.Value >= tile.Ice.Value && tile.WaterLevel.Value >= tile.Snow.Value:
+		return "flooded"
+	case tile.Ice.Value >= tile.Snow.Value:
+		return "icy"
+	default:
+		return "snowy"
+	}
+}
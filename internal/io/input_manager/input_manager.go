@@ -148,8 +148,9 @@ func (i *InputManager) MouseDown(evname string, ev interface{}) {
 
 			switch me.Button {
 			case window.MouseButton1:
-				tile.AddWater(chem.CubicMetresToLitres(1))
-				i.State.Quantities[chem.Water].Value += 1
+				water := chem.CubicMetresToLitres(1)
+				tile.AddWater(water)
+				i.State.Quantities[chem.Water].Value += water
 			case window.MouseButton2:
 				gui.Open(gui.TileContextMenu, false)
 			}
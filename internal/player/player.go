@@ -57,7 +57,7 @@ func (p *Player) Update(deltaTime float32, maxX, maxZ float32, centre math32.Vec
 	if len(i) != 0 {
 		object = i[0].Object.GetNode()
 
-		if entity := p.State.EntityOf(object); entity != nil {
+		if entity := p.State.EntityOf(object.Name()); entity != nil {
 			p.State.LookingAt = entity
 		}
 	}
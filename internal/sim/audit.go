@@ -0,0 +1,106 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+
+	"cbeimers113/strands/internal/chem"
+)
+
+var (
+	// How many ticks pass between audits of the simulation's totals, audits are skipped if this isn't positive
+	AuditInterval = 240
+
+	// How far a recorded total can drift from the true total before it's reported, as a fraction of the true total
+	AuditTolerance = 1e-3
+)
+
+// Drift is a recorded total that no longer matches the amount of the substance actually in the simulation
+type Drift struct {
+	Name     chem.ElementType
+	Recorded chem.Quantity
+	Actual   chem.Quantity
+}
+
+// Describe the drift between a recorded total and the true one
+func (d Drift) String() string {
+	return fmt.Sprintf("The recorded %s total [%s] drifted from the actual total [%s]", d.Name, d.Recorded, d.Actual)
+}
+
+// Count the true total of every tracked substance from the tiles and the atmosphere
+func (s *Simulation) totals() map[chem.ElementType]*chem.Quantity {
+	totals := make(map[chem.ElementType]*chem.Quantity)
+
+	var water, soil, ice float64
+	for _, tile := range s.GetTiles() {
+		water += float64(tile.WaterLevel.Value)
+		soil += float64(tile.SoilMoisture.Value)
+		ice += float64(tile.Ice.Value + tile.Snow.Value)
+	}
+
+	totals[chem.Water] = &chem.Quantity{Value: float32(water), Units: chem.Litre}
+	totals[chem.SoilWater] = &chem.Quantity{Value: float32(soil), Units: chem.Litre}
+	totals[chem.Ice] = &chem.Quantity{Value: float32(ice), Units: chem.Litre}
+
+	cells := s.atmosphere.GetCells()
+	for _, element := range chem.Elements {
+		var total float64
+		for _, cell := range cells {
+			total += float64(cell.Quantities[element.Type].Value)
+		}
+
+		// Water in the air is counted separately from water on the surface
+		name := element.Type
+		if name == chem.Water {
+			name = chem.WaterVapour
+		}
+
+		totals[name] = &chem.Quantity{Value: float32(total), Units: element.Units}
+	}
+
+	return totals
+}
+
+// Record the true total of every tracked substance
+func (s *Simulation) tally() {
+	for name, total := range s.totals() {
+		s.State.Quantities[name] = total
+	}
+}
+
+// Audit recounts every tracked substance and returns the recorded totals that have drifted from the true ones.
+// The recorded totals are replaced with the true ones, so drift doesn't build up between audits.
+func (s *Simulation) Audit() (drifts []Drift) {
+	totals := s.totals()
+
+	for _, name := range s.State.QuantityNames() {
+		recorded := *s.State.Quantities[name]
+		actual, ok := totals[name]
+		if !ok {
+			continue
+		}
+
+		if difference, err := recorded.Sub(*actual); err != nil || drifted(difference.Value, actual.Value) {
+			drifts = append(drifts, Drift{Name: name, Recorded: recorded, Actual: *actual})
+		}
+	}
+
+	for name, actual := range totals {
+		s.State.Quantities[name] = actual
+	}
+
+	return
+}
+
+// Check whether a difference from a total is too large to be rounding error
+func drifted(difference, total float32) bool {
+	return math.Abs(float64(difference)) > AuditTolerance*math.Max(1, math.Abs(float64(total)))
+}
+
+// Audit the simulation's totals, and report any drift to the player and the log
+func (s *Simulation) audit() {
+	for _, drift := range s.Audit() {
+		fmt.Println(drift)
+		s.notify(drift.String())
+	}
+}
@@ -0,0 +1,102 @@
+package sim
+
+import (
+	"github.com/aquilax/go-perlin"
+
+	"cbeimers113/strands/internal/entity"
+)
+
+// Biome is a kind of habitat, which decides what the tiles generated in it start out like
+type Biome struct {
+	Name         string
+	Tile         string  // The name of the tile type that tiles spawn as
+	Temperature  float32 // The temperature in °C that tiles start at
+	Water        float32 // The standing water in L that tiles start with
+	Saturation   float32 // How full of water the soil under tiles starts out, from 0 for dry to 1 for saturated
+	PlantDensity float32 // How many plants grow on each tile to begin with, on average
+}
+
+var Desert Biome = Biome{Name: "desert", Tile: "sand", Temperature: 32, Water: 0, Saturation: 0.05, PlantDensity: 0.02}
+var Grassland Biome = Biome{Name: "grassland", Tile: "grass", Temperature: 22, Water: 5, Saturation: 0.5, PlantDensity: 0.3}
+var Forest Biome = Biome{Name: "forest", Tile: "grass", Temperature: 18, Water: 10, Saturation: 0.7, PlantDensity: 1.5}
+var Tundra Biome = Biome{Name: "tundra", Tile: "dirt", Temperature: 2, Water: 5, Saturation: 0.4, PlantDensity: 0.05}
+var Marsh Biome = Biome{Name: "marsh", Tile: "dirt", Temperature: 20, Water: 150, Saturation: 1, PlantDensity: 0.5}
+
+// Store list of biomes
+var Biomes []Biome = []Biome{
+	Desert,
+	Grassland,
+	Forest,
+	Tundra,
+	Marsh,
+}
+
+const (
+	// The temperature in °C of the lowest ground on the map, before the climate varies it
+	baseTemperature = 24
+
+	// How much colder the highest ground on the map is than the lowest, in °C
+	lapseRate = 22
+
+	// How far the climate can make the temperature vary either side of what it would be for the ground's height, in °C
+	temperatureSpread = 6
+
+	// The size in tiles of regions of warmer or wetter climate
+	climateScale = 24
+)
+
+// Choose the biome for ground at an elevation from 0 for the lowest on the map to 1 for the highest,
+// with a temperature in °C and a moisture from 0 for the driest on the map to 1 for the wettest
+func chooseBiome(elevation, temperature, moisture float32) Biome {
+	switch {
+	case temperature < 5:
+		return Tundra
+	case moisture > 0.65 && elevation < 0.4:
+		return Marsh
+	case moisture < 0.35:
+		return Desert
+	case moisture > 0.55:
+		return Forest
+	default:
+		return Grassland
+	}
+}
+
+// Get the climate of the map, which gives the temperature in °C and the moisture from 0 to 1 of the ground
+// at a tile with an elevation from 0 for the lowest on the map to 1 for the highest.
+// High ground is colder and drier, and ground under the sea is soaked.
+func (s *Simulation) climate() func(x, z int, elevation float32, underwater bool) (float32, float32) {
+	warmth := perlin.NewPerlin(2, 2, 2, s.State.Rand.Int63())
+	wetness := perlin.NewPerlin(2, 2, 3, s.State.Rand.Int63())
+
+	return func(x, z int, elevation float32, underwater bool) (float32, float32) {
+		px, pz := float64(x)/climateScale, float64(z)/climateScale
+		temperature := baseTemperature - lapseRate*elevation + temperatureSpread*float32(warmth.Noise2D(px, pz))
+		moisture := 0.8*float32(wetness.Noise2D(px, pz)+1)/2 + 0.2*(1-elevation)
+
+		if underwater {
+			moisture = 1
+		}
+
+		return temperature, moisture
+	}
+}
+
+// Spawn the plants that a biome starts out with on a tile, the fractional part of the density is the chance of one more.
+// The plants in each biome are all one species, descended from a founder that's made up the first time the biome is planted.
+func (s *Simulation) plantBiome(tile *entity.Tile, biome Biome, founders map[string]entity.Genome) {
+	plants := int(biome.PlantDensity)
+	if s.State.Rand.Float32() < biome.PlantDensity-float32(plants) {
+		plants++
+	}
+
+	for i := 0; i < plants; i++ {
+		founder, ok := founders[biome.Name]
+		if !ok {
+			founder = entity.RandomGenome(s.State.Rand)
+			founders[biome.Name] = founder
+		}
+
+		tile.AddPlant(founder.Mutate(s.State.Rand))
+	}
+}
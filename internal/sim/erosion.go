@@ -0,0 +1,127 @@
+package sim
+
+import (
+	"cbeimers113/strands/internal/entity"
+)
+
+var (
+	// The volume of sediment in m³ that each litre of water flowing down a slope of 1 can carry
+	SedimentCapacity float32 = 1e-4
+
+	// The fraction of the sediment that the water could still carry which it picks up off a sand tile per second,
+	// other tiles are worn away more slowly according to their erodibility
+	ErosionRate float32 = 0.01
+
+	// The fraction of the sediment that the water can't carry which settles out of it per second
+	DepositionRate float32 = 0.02
+
+	// How deep a layer of silt has to build up on a tile before it turns to sand, in metres
+	SandDepth float32 = 0.05
+)
+
+// The gentlest slope that water flowing between tiles is treated as running down, so that water flowing
+// across flat ground still wears it away a little
+const minSlope float32 = 0.01
+
+// Let the water that flowed this tick wear away the tiles it flowed over and carry the sediment along with it,
+// dropping sediment wherever the water slows down, over a number of seconds of simulated time.
+// Worn away tiles expose the bedrock underneath once their topsoil is gone, and tiles that build up enough silt turn to sand.
+func (s *Simulation) erode(seconds float32) {
+	width := s.Cfg.Simulation.Width
+	depth := s.Cfg.Simulation.Depth
+
+	s.allocateFlow()
+
+	for x := 0; x < width; x++ {
+		for z := 0; z < depth; z++ {
+			tile := s.tilemap[x][z]
+			outflow := &s.outflow[x+z*width]
+
+			// The water running downhill out of the tile decides how much sediment it can carry
+			var capacity, flowed float32
+			for i, amount := range outflow {
+				if amount <= 0 {
+					continue
+				}
+
+				neighbour := tile.Neighbours[i]
+				slope := max(minSlope, (tile.WorldY-neighbour.WorldY)/tileSpacing)
+				capacity += SedimentCapacity * amount * slope
+				flowed += amount
+			}
+
+			surface := s.surfaceY(tile)
+			if tile.Sediment < capacity {
+				s.wear(tile, min(1, ErosionRate*tile.Type.Erodibility*seconds)*(capacity-tile.Sediment))
+			} else {
+				s.settle(tile, min(1, DepositionRate*seconds)*(tile.Sediment-capacity))
+			}
+
+			if s.surfaceY(tile) != surface {
+				s.atmosphere.SetGround(x, z, s.surfaceY(tile))
+			}
+
+			// The sediment is carried along with the water, in proportion to how much of the tile's water flowed to each neighbour
+			if flowed > 0 {
+				water := tile.WaterLevel.Value + flowed
+				for i, amount := range outflow {
+					if amount <= 0 {
+						continue
+					}
+
+					neighbour := tile.Neighbours[i]
+					carried := tile.Sediment * amount / water
+					s.flow[x+z*width] -= carried
+					s.flow[neighbour.MapX+neighbour.MapZ*width] += carried
+				}
+			}
+
+			*outflow = [6]float32{}
+		}
+	}
+
+	for x := 0; x < width; x++ {
+		for z := 0; z < depth; z++ {
+			tile := s.tilemap[x][z]
+			tile.Sediment = max(0, tile.Sediment+s.flow[x+z*width])
+			s.flow[x+z*width] = 0
+		}
+	}
+}
+
+// Wear away a volume of ground in m³ from a tile into the water on it, first from any silt on the tile and then its topsoil.
+// Once the topsoil is gone the bedrock is exposed and the tile turns to stone.
+func (s *Simulation) wear(tile *entity.Tile, volume float32) {
+	if volume <= 0 {
+		return
+	}
+
+	// A tile is a square metre, so a cubic metre of sediment is a metre of height
+	tile.WorldY -= volume
+	tile.Sediment += volume
+
+	silt := min(tile.Silt, volume)
+	tile.Silt -= silt
+	tile.Topsoil.Value = max(0, tile.Topsoil.Value-(volume-silt))
+
+	if tile.Topsoil.Value <= 0 && tile.Silt <= 0 && tile.Type != entity.Stone {
+		tile.Type = entity.Stone
+	}
+}
+
+// Settle a volume of sediment in m³ out of the water on a tile into a layer of silt, which turns the tile to sand once it's deep enough
+func (s *Simulation) settle(tile *entity.Tile, volume float32) {
+	if volume <= 0 {
+		return
+	}
+
+	tile.WorldY += volume
+	tile.Sediment -= volume
+	tile.Silt += volume
+
+	if tile.Silt >= SandDepth && tile.Type != entity.Sand {
+		tile.Type = entity.Sand
+		tile.Topsoil.Value += tile.Silt
+		tile.Silt = 0
+	}
+}
@@ -0,0 +1,172 @@
+package sim
+
+import (
+	"fmt"
+
+	"github.com/g3n/engine/math32"
+
+	"cbeimers113/strands/internal/chem"
+	"cbeimers113/strands/internal/entity"
+)
+
+var (
+	// The chance per second that lightning strikes a tile caught in a storm
+	LightningRate float32 = 1e-5
+
+	// The chance per second that a burning plant sets a bone dry plant next to it alight, wetter plants catch less easily
+	FireSpread float32 = 0.01
+
+	// The wind speed in m/s that doubles how easily fire spreads downwind, and stops it spreading upwind
+	FireWindSpeed float32 = 5
+
+	// The dry mass of a burning plant in kg that burns away per second
+	BurnRate float32 = 5e-6
+
+	// How quickly a plant's moisture follows how much of the water it wants it's getting out of the soil, per second
+	PlantHydration float32 = 1e-4
+
+	// The fraction of the smoke in the air touching a tile that settles out onto it as ash per second
+	SmokeSettling float32 = 1e-4
+
+	// The fraction of the ash on a tile that's weathered away into the soil per second
+	AshWeathering float32 = 4e-7
+)
+
+// What burning a kg of plant matter takes from the air and gives off into it, treating the plant as carbohydrate.
+// Photosynthesis takes the same carbon dioxide and water back to build up a kg of plant matter.
+const (
+	oxygenPerBiomass  float32 = 1067 // g of oxygen used
+	carbonPerBiomass  float32 = 1467 // g of carbon dioxide given off
+	vapourPerBiomass  float32 = 0.6  // L of water given off as vapour
+	smokePerBiomass   float32 = 20   // g of soot given off as smoke
+	ashPerBiomass     float32 = 0.05 // kg of ash left behind
+	lightningMoisture float32 = 0.5  // The wettest a plant can be for lightning to set it alight
+)
+
+// Burn the plants that are on fire over a number of seconds of simulated time. Lightning in a storm can set dry plants alight,
+// and fire spreads from burning plants to the plants around them, faster downwind and not at all onto wet ground.
+// Burning plants use up oxygen and give off carbon dioxide, water vapour and smoke, and leave ash behind once they're gone.
+func (s *Simulation) burn(seconds float32) {
+	tiles := s.GetTiles()
+
+	// Plan every ignition from the fires at the start of the tick, so that fire only spreads one tile per tick
+	var ignited []*entity.Plant
+	for _, tile := range tiles {
+		if s.storm && tile.Rain >= StormIntensity && s.State.Rand.Float32() < LightningRate*seconds {
+			if plant := s.strike(tile); plant != nil {
+				ignited = append(ignited, plant)
+			}
+		}
+
+		if !tile.Burning() {
+			continue
+		}
+
+		wind := s.cellAbove(tile).Wind
+		for i := -1; i < len(tile.Neighbours); i++ {
+			target, spread := tile, float32(1)
+
+			if i >= 0 {
+				if target = tile.Neighbours[i]; target == nil {
+					continue
+				}
+
+				direction := math32.Vector3{X: target.WorldX() - tile.WorldX(), Z: target.WorldZ() - tile.WorldZ()}
+				direction.Normalize()
+				spread = max(0, 1+wind.Dot(&direction)/FireWindSpeed)
+			}
+
+			spread *= target.Flammability()
+			for _, plant := range target.Plants {
+				if !plant.Burning && s.State.Rand.Float32() < FireSpread*seconds*spread*(1-plant.Moisture) {
+					ignited = append(ignited, plant)
+				}
+			}
+		}
+	}
+
+	var oxygen, carbon, vapour, smoke float64
+	var burning bool
+
+	for _, tile := range tiles {
+		air := s.cellAbove(tile).Quantities
+
+		// Fires on flooded tiles go out
+		doused := tile.Flammability() <= 0
+		alive := tile.Plants[:0]
+
+		for _, plant := range tile.Plants {
+			if plant.Burning && doused {
+				plant.Burning = false
+			}
+
+			if plant.Burning {
+				// A fire can't burn any faster than the air around it can feed it oxygen
+				burnt := min(plant.Biomass, BurnRate*seconds, air[chem.Oxygen].Value/oxygenPerBiomass)
+				plant.Biomass -= burnt
+				tile.Ash += burnt * ashPerBiomass
+
+				air[chem.Oxygen].Value -= burnt * oxygenPerBiomass
+				air[chem.CarbonDioxide].Value += burnt * carbonPerBiomass
+				air[chem.Water].Value += burnt * vapourPerBiomass
+				air[chem.Smoke].Value += burnt * smokePerBiomass
+
+				oxygen -= float64(burnt * oxygenPerBiomass)
+				carbon += float64(burnt * carbonPerBiomass)
+				vapour += float64(burnt * vapourPerBiomass)
+				smoke += float64(burnt * smokePerBiomass)
+				burning = true
+			}
+
+			if plant.Biomass > 0 {
+				alive = append(alive, plant)
+			}
+		}
+
+		// Clear the plants that burnt away out of the tile without holding on to them
+		for i := len(alive); i < len(tile.Plants); i++ {
+			tile.Plants[i] = nil
+		}
+		tile.Plants = alive
+
+		// Smoke settles out of the air onto the ground as ash, and the ash is slowly weathered away
+		settled := air[chem.Smoke].Value * min(1, SmokeSettling*seconds)
+		air[chem.Smoke].Value -= settled
+		smoke -= float64(settled)
+		tile.Ash += settled / 1000
+		tile.Ash -= tile.Ash * min(1, AshWeathering*seconds)
+	}
+
+	for _, plant := range ignited {
+		plant.Burning = true
+	}
+
+	s.State.Quantities[chem.Oxygen].Value += float32(oxygen)
+	s.State.Quantities[chem.CarbonDioxide].Value += float32(carbon)
+	s.State.Quantities[chem.WaterVapour].Value += float32(vapour)
+	s.State.Quantities[chem.Smoke].Value += float32(smoke)
+
+	// Let the player know once every fire on the map has gone out
+	burning = burning || len(ignited) > 0
+	if s.fire && !burning {
+		s.notify("The fire has burnt out")
+	}
+	s.fire = burning
+}
+
+// Strike a tile with lightning, setting the driest plant on it alight if it's dry enough to catch. Returns the plant that caught fire.
+func (s *Simulation) strike(tile *entity.Tile) *entity.Plant {
+	var driest *entity.Plant
+	for _, plant := range tile.Plants {
+		if !plant.Burning && (driest == nil || plant.Moisture < driest.Moisture) {
+			driest = plant
+		}
+	}
+
+	if driest == nil || driest.Moisture > lightningMoisture || tile.Flammability() <= 0 {
+		return nil
+	}
+
+	s.notify(fmt.Sprintf("Lightning started a fire at (%d, %d)", tile.MapX, tile.MapZ))
+	return driest
+}
@@ -0,0 +1,42 @@
+package sim
+
+import (
+	"cbeimers113/strands/internal/chem"
+)
+
+// The energy in J it takes to melt the ice that a litre of water freezes into, which the water gives off again as it freezes
+const LatentHeatOfFusion float32 = 334_000
+
+// Freeze the standing water on tiles that have cooled below 0°C, and melt the ice and snow on tiles that have warmed above it.
+// Water gives off heat as it freezes and ice takes heat in as it melts, so a tile only freezes or melts as much as it takes
+// to bring it back to 0°C, and deep water or thick ice takes a long time to turn.
+func (s *Simulation) freeze() {
+	var frozen float64
+
+	for _, tile := range s.GetTiles() {
+		temperature := &tile.Temperature.Value
+		capacity := heatCapacity(tile)
+
+		switch {
+		case *temperature < 0 && tile.WaterLevel.Value > 0:
+			amount := min(tile.WaterLevel.Value, -*temperature*capacity/LatentHeatOfFusion)
+			tile.AddWater(-amount)
+			tile.Ice.Value += amount
+			*temperature += amount * LatentHeatOfFusion / capacity
+			frozen += float64(amount)
+
+		case *temperature > 0 && tile.Ice.Value+tile.Snow.Value > 0:
+			// The snow lies on top of the ice, so it melts first
+			amount := min(tile.Ice.Value+tile.Snow.Value, *temperature*capacity/LatentHeatOfFusion)
+			snow := min(tile.Snow.Value, amount)
+			tile.Snow.Value -= snow
+			tile.Ice.Value = max(0, tile.Ice.Value-(amount-snow))
+			tile.AddWater(amount)
+			*temperature -= amount * LatentHeatOfFusion / capacity
+			frozen -= float64(amount)
+		}
+	}
+
+	s.State.Quantities[chem.Water].Value -= float32(frozen)
+	s.State.Quantities[chem.Ice].Value += float32(frozen)
+}
@@ -0,0 +1,121 @@
+package sim
+
+import (
+	"cbeimers113/strands/internal/chem"
+)
+
+var (
+	// How fast water seeps through the soil between neighbouring tiles, as a fraction of the slower tile's permeability
+	// for each unit of difference in how saturated their soil is
+	SoilSeepage float32 = 1
+
+	// How much water a plant draws out of the soil and gives off into the air, in L/s for each of its leaves
+	Transpiration float32 = 2e-6
+)
+
+// Soak standing water into the soil under each tile, and let it seep through the soil between neighbouring tiles,
+// over a number of seconds of simulated time. The water soaks in first, then the seepage is planned from the soil moisture
+// that leaves and applied all at once, so the order that tiles are visited in doesn't matter.
+func (s *Simulation) soakWater(seconds float32) {
+	width := s.Cfg.Simulation.Width
+	depth := s.Cfg.Simulation.Depth
+
+	s.allocateFlow()
+
+	// Water soaks in as fast as the tile lets it, until the soil can't hold any more
+	var soaked float64
+	for x := 0; x < width; x++ {
+		for z := 0; z < depth; z++ {
+			tile := s.tilemap[x][z]
+
+			room := max(0, tile.Type.SoilCapacity-tile.SoilMoisture.Value)
+			amount := min(tile.WaterLevel.Value, tile.Type.Permeability*seconds, room)
+			if amount > 0 {
+				tile.AddWater(-amount)
+				tile.SoilMoisture.Value += amount
+				soaked += float64(amount)
+			}
+		}
+	}
+
+	for x := 0; x < width; x++ {
+		for z := 0; z < depth; z++ {
+			tile := s.tilemap[x][z]
+
+			// Only visit the first three neighbours so that each pair of tiles is visited once
+			for _, neighbour := range tile.Neighbours[:3] {
+				if neighbour == nil {
+					continue
+				}
+
+				permeability := min(tile.Type.Permeability, neighbour.Type.Permeability)
+				seepage := SoilSeepage * permeability * (tile.Saturation() - neighbour.Saturation()) * seconds
+
+				// A tile has six neighbours, so moving no more than a sixth of what the wetter tile has, or of what the drier
+				// one has room for, across each pair never empties a tile or fills it past what its soil can hold
+				if seepage > 0 {
+					seepage = min(seepage, tile.SoilMoisture.Value/6, (neighbour.Type.SoilCapacity-neighbour.SoilMoisture.Value)/6)
+				} else {
+					seepage = -min(-seepage, neighbour.SoilMoisture.Value/6, (tile.Type.SoilCapacity-tile.SoilMoisture.Value)/6)
+				}
+
+				s.flow[x+z*width] -= seepage
+				s.flow[neighbour.MapX+neighbour.MapZ*width] += seepage
+			}
+		}
+	}
+
+	// Rounding can still leave a hair below zero, which is booked so the totals keep matching the tiles
+	var clamped float64
+	for x := 0; x < width; x++ {
+		for z := 0; z < depth; z++ {
+			moisture := &s.tilemap[x][z].SoilMoisture.Value
+			*moisture += s.flow[x+z*width]
+			s.flow[x+z*width] = 0
+
+			if *moisture < 0 {
+				clamped -= float64(*moisture)
+				*moisture = 0
+			}
+		}
+	}
+
+	s.State.Quantities[chem.Water].Value -= float32(soaked)
+	s.State.Quantities[chem.SoilWater].Value += float32(soaked + clamped)
+}
+
+// Let every plant draw water out of the soil under its tile and give it off into the air above, over a number of seconds
+// of simulated time. When the soil can't meet the demand of every plant on a tile, they share what's left of it,
+// and plants that go short of water slowly dry out.
+func (s *Simulation) transpire(seconds float32) {
+	var transpired float64
+
+	for x := 0; x < s.Cfg.Simulation.Width; x++ {
+		for z := 0; z < s.Cfg.Simulation.Depth; z++ {
+			tile := s.tilemap[x][z]
+
+			var demand float32
+			for _, plant := range tile.Plants {
+				demand += Transpiration * float32(plant.NumLeaves) * seconds
+			}
+
+			amount := min(demand, tile.SoilMoisture.Value)
+			if demand > 0 {
+				for _, plant := range tile.Plants {
+					plant.Moisture += (amount/demand - plant.Moisture) * min(1, PlantHydration*seconds)
+				}
+			}
+
+			if amount <= 0 {
+				continue
+			}
+
+			tile.SoilMoisture.Value -= amount
+			s.cellAbove(tile).Quantities[chem.Water].Value += amount
+			transpired += float64(amount)
+		}
+	}
+
+	s.State.Quantities[chem.SoilWater].Value -= float32(transpired)
+	s.State.Quantities[chem.WaterVapour].Value += float32(transpired)
+}
@@ -0,0 +1,72 @@
+package sim
+
+import (
+	"fmt"
+	"time"
+
+	"cbeimers113/strands/internal/config"
+	"cbeimers113/strands/internal/state"
+)
+
+// HeadlessOptions configures a simulation run without a window or renderer
+type HeadlessOptions struct {
+	Ticks    int    // How many ticks to advance the simulation by
+	LoadFile string // If set, load the simulation from this save file instead of generating a new one
+	SaveFile string // If set, store the simulation to this save file once the run is complete
+	Seed     int64  // The seed for a newly generated simulation
+}
+
+// RunHeadless loads or generates a simulation and advances it by a fixed number of ticks
+func RunHeadless(cfg *config.Config, opts HeadlessOptions) error {
+	var (
+		s      *Simulation
+		camera state.CamData
+	)
+
+	if opts.Ticks < 0 {
+		return fmt.Errorf("tick count [%d] can't be negative", opts.Ticks)
+	}
+
+	if opts.LoadFile != "" {
+		st, cells, tiles, camData, err := state.LoadSave(cfg, opts.LoadFile)
+		if err != nil {
+			return err
+		}
+
+		s = Load(cfg, st, tiles, cells)
+		camera = camData
+		fmt.Printf("Loaded simulation from %s\n", opts.LoadFile)
+	} else {
+		s = New(cfg, state.New(cfg, opts.Seed))
+		fmt.Printf("Created new simulation with seed %d\n", opts.Seed)
+	}
+
+	start := time.Now()
+
+	for i := 1; i <= opts.Ticks; i++ {
+		s.Update()
+
+		for _, message := range s.Events() {
+			fmt.Printf("Tick %d: %s\n", i, message)
+		}
+
+		if i%1000 == 0 || i == opts.Ticks {
+			fmt.Printf("Tick %d/%d: %s\n", i, opts.Ticks, s.State.Clock)
+		}
+	}
+
+	fmt.Printf("Ran %d ticks in %s\n", opts.Ticks, time.Since(start).Round(time.Millisecond))
+	for _, name := range s.State.QuantityNames() {
+		fmt.Printf("%s: %s\n", name, s.State.Quantities[name])
+	}
+
+	if opts.SaveFile != "" {
+		if err := state.StoreSave(opts.SaveFile, s.State, s.GetAtmosphere(), s.GetTiles(), camera); err != nil {
+			return fmt.Errorf("couldn't create save file [%s]: %w", opts.SaveFile, err)
+		}
+
+		fmt.Printf("Saved simulation to %s\n", opts.SaveFile)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package sim
+
+import (
+	"github.com/g3n/engine/math32"
+
+	"cbeimers113/strands/internal/atmosphere"
+	"cbeimers113/strands/internal/chem"
+	"cbeimers113/strands/internal/entity"
+)
+
+var (
+	// The power in W of the sunlight falling on each square metre of a tile when the sun is directly overhead
+	SolarIntensity float32 = 500
+
+	// The power in W that flows between a square metre of a tile and the air touching it for each °C of difference between them
+	SurfaceExchange float32 = 10
+)
+
+const (
+	// The energy in J it takes to warm a litre of water by 1°C
+	WaterHeatCapacity float32 = 4186
+
+	// The energy in J it takes to warm the ice that a litre of water freezes into by 1°C
+	IceHeatCapacity float32 = 2100
+
+	// The Stefan-Boltzmann constant in W/m²K⁴
+	stefanBoltzmann float32 = 5.67e-8
+)
+
+// Warm the tiles in the sunlight and let them radiate heat out to the sky over a number of seconds of simulated time,
+// then exchange heat between each tile and the air touching it. The air radiates its own heat away as well.
+func (s *Simulation) heat(seconds float32) {
+	sunlight := SolarIntensity * max(0, s.State.Clock.SunElevation())
+
+	for x := 0; x < s.Cfg.Simulation.Width; x++ {
+		for z := 0; z < s.Cfg.Simulation.Depth; z++ {
+			tile := s.tilemap[x][z]
+			air := s.cellAbove(tile)
+			capacity := heatCapacity(tile)
+
+			// Heat radiated by a black body at the tile's temperature, less what the sky radiates back at it
+			radiated := stefanBoltzmann * (math32.Pow(chem.CelciusToKelvin(tile.Temperature.Value), 4) - math32.Pow(chem.CelciusToKelvin(atmosphere.SkyTemperature), 4))
+			tile.Temperature.Value += (sunlight - radiated) * seconds / capacity
+
+			// Move heat across the surface, at most as much as would bring the tile and the air to the same temperature
+			conductance := min(SurfaceExchange*seconds, capacity*atmosphere.AirHeatCapacity/(capacity+atmosphere.AirHeatCapacity))
+			flow := conductance * (tile.Temperature.Value - air.Temperature)
+			tile.Temperature.Value -= flow / capacity
+			air.Temperature += flow / atmosphere.AirHeatCapacity
+		}
+	}
+
+	s.atmosphere.Radiate(seconds)
+}
+
+// Get the energy in J it takes to warm a tile and the water, ice and snow on it by 1°C,
+// water warms slowly so wet tiles change temperature slowly
+func heatCapacity(tile *entity.Tile) float32 {
+	return tile.Type.HeatCapacity + tile.WaterLevel.Value*WaterHeatCapacity + (tile.Ice.Value+tile.Snow.Value)*IceHeatCapacity
+}
@@ -0,0 +1,72 @@
+package sim
+
+import (
+	"cbeimers113/strands/internal/chem"
+	"cbeimers113/strands/internal/entity"
+)
+
+// The fraction of the difference between a coastal tile's water and sea level that's made up with the ocean per second
+var OceanExchange float32 = 0.05
+
+// Check whether a tile is on the edge of the map, where it borders the ocean
+func isCoastal(tile *entity.Tile) bool {
+	for _, neighbour := range tile.Neighbours {
+		if neighbour == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get how much water in L a tile holds when the water on it sits at sea level
+func (s *Simulation) seaWater(tile *entity.Tile) float32 {
+	return max(0, chem.CubicMetresToLitres(s.Cfg.Water.SeaLevel-tile.WorldY-entity.TileHeight))
+}
+
+// Let the tiles on the edge of the map drain into the ocean around it, or be refilled from it, over a number of seconds
+// of simulated time. The ocean holds endless water, so its surface always stays at sea level.
+// Sea ice counts towards the sea level, but only the water under it is exchanged.
+func (s *Simulation) exchangeOcean(seconds float32) {
+	rate := min(1, OceanExchange*seconds)
+
+	var exchanged float64
+	for _, tile := range s.GetTiles() {
+		if !isCoastal(tile) {
+			continue
+		}
+
+		amount := max(-tile.WaterLevel.Value, (s.seaWater(tile)-tile.WaterLevel.Value-tile.Ice.Value)*rate)
+		tile.AddWater(amount)
+		exchanged += float64(amount)
+	}
+
+	s.State.Quantities[chem.Water].Value += float32(exchanged)
+}
+
+// Flood every tile below sea level that the ocean can reach from the edge of the map up to sea level.
+// Hollows below sea level that are walled off from the ocean are left dry.
+func (s *Simulation) flood() {
+	var queue []*entity.Tile
+	flooded := make(map[*entity.Tile]bool)
+
+	for _, tile := range s.GetTiles() {
+		if isCoastal(tile) && s.seaWater(tile) > 0 {
+			queue = append(queue, tile)
+			flooded[tile] = true
+		}
+	}
+
+	for len(queue) > 0 {
+		tile := queue[0]
+		queue = queue[1:]
+		tile.WaterLevel.Value = max(tile.WaterLevel.Value, s.seaWater(tile))
+
+		for _, neighbour := range tile.Neighbours {
+			if neighbour != nil && !flooded[neighbour] && s.seaWater(neighbour) > 0 {
+				queue = append(queue, neighbour)
+				flooded[neighbour] = true
+			}
+		}
+	}
+}
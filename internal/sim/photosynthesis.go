@@ -0,0 +1,72 @@
+package sim
+
+import (
+	"cbeimers113/strands/internal/chem"
+)
+
+var (
+	// The dry mass in kg that a plant builds up for each J of sunlight its leaves catch, which grows a well watered plant
+	// to its full height in about the time it takes to mature
+	PhotosynthesisYield float32 = 5e-9
+
+	// The carbon dioxide in g in the air touching a plant that lets it photosynthesise at full speed, thinner air slows it down
+	CarbonSaturation float32 = 0.77
+
+	// The fraction of its dry mass that a plant burns up per second to stay alive, day and night
+	Respiration float32 = 5e-7
+)
+
+// Let the plants grow over a number of seconds of simulated time. Photosynthesis is burning run backwards: a plant's leaves
+// catch sunlight to turn carbon dioxide from the air and water from the soil into dry mass, giving off oxygen. Bigger and
+// more leaves catch more light, and leaves closer to the ideal green make better use of it, but dry plants and thin air
+// slow it down. Plants respire some of their mass back into the air all the time, so they waste away in the dark.
+func (s *Simulation) photosynthesise(seconds float32) {
+	sunlight := SolarIntensity * max(0, s.State.Clock.SunElevation())
+
+	var oxygen, carbon, vapour, soaked float64
+
+	for x := 0; x < s.Cfg.Simulation.Width; x++ {
+		for z := 0; z < s.Cfg.Simulation.Depth; z++ {
+			tile := s.tilemap[x][z]
+			if len(tile.Plants) == 0 {
+				continue
+			}
+
+			air := s.cellAbove(tile).Quantities
+
+			// The plants on a tile share the air and soil water, the first plants get first pick when there is little left
+			for _, plant := range tile.Plants {
+				if plant.Burning {
+					continue
+				}
+
+				// Photosynthesis can't take in more carbon dioxide or water than there is
+				rate := min(1, air[chem.CarbonDioxide].Value/CarbonSaturation) * plant.Moisture * plant.Greenness()
+				fixed := min(
+					sunlight*plant.LeafArea()*rate*PhotosynthesisYield*seconds,
+					air[chem.CarbonDioxide].Value/carbonPerBiomass,
+					tile.SoilMoisture.Value/vapourPerBiomass,
+				)
+
+				// Respiration can't use more oxygen than there is
+				respired := min(plant.Biomass*min(1, Respiration*seconds), air[chem.Oxygen].Value/oxygenPerBiomass)
+
+				plant.Biomass += fixed - respired
+				tile.SoilMoisture.Value -= fixed * vapourPerBiomass
+				air[chem.CarbonDioxide].Value += (respired - fixed) * carbonPerBiomass
+				air[chem.Oxygen].Value += (fixed - respired) * oxygenPerBiomass
+				air[chem.Water].Value += respired * vapourPerBiomass
+
+				oxygen += float64((fixed - respired) * oxygenPerBiomass)
+				carbon += float64((respired - fixed) * carbonPerBiomass)
+				vapour += float64(respired * vapourPerBiomass)
+				soaked += float64(fixed * vapourPerBiomass)
+			}
+		}
+	}
+
+	s.State.Quantities[chem.Oxygen].Value += float32(oxygen)
+	s.State.Quantities[chem.CarbonDioxide].Value += float32(carbon)
+	s.State.Quantities[chem.WaterVapour].Value += float32(vapour)
+	s.State.Quantities[chem.SoilWater].Value -= float32(soaked)
+}
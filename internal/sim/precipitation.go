@@ -0,0 +1,88 @@
+package sim
+
+import (
+	"fmt"
+
+	"cbeimers113/strands/internal/chem"
+	"cbeimers113/strands/internal/entity"
+)
+
+var (
+	// The fraction of a cell's excess water vapour that falls out of the air as rain per second
+	PrecipitationRate float32 = 0.02
+
+	// The rain intensity in mm/h above which a tile counts as being caught in a storm.
+	// The air only holds a fraction of a litre of water over each tile, so this is much lighter than a storm on Earth.
+	StormIntensity float32 = 0.002
+
+	// The fraction of the map that has to be caught in a storm for it to count as one,
+	// the storm is over once less than half of this fraction is still caught in it
+	StormCoverage float32 = 0.1
+)
+
+// Rain any water vapour that the air above each tile can't hold down onto the tile, over a number of seconds of simulated time.
+// The cell at the surface is left to condensation, so rain only comes from the cells further up the column.
+// Where the air at the surface is below freezing it falls as snow, which lies on the tile until it melts.
+func (s *Simulation) precipitate(seconds float32) {
+	var rained, snowed float64
+	var stormTiles, stormX, stormZ int
+
+	for x := 0; x < s.Cfg.Simulation.Width; x++ {
+		for z := 0; z < s.Cfg.Simulation.Depth; z++ {
+			tile := s.tilemap[x][z]
+			tile.Rain = 0
+
+			column := s.atmosphere.Precipitate(x, z, s.surfaceY(tile)+1, min(1, PrecipitationRate*seconds))
+			if column <= 0 || seconds <= 0 {
+				continue
+			}
+
+			// A litre over a square metre column is a millimetre of rain
+			intensity := column / seconds * 60 * 60
+			if s.cellAbove(tile).Temperature < 0 {
+				tile.Snow.Value += column
+				snowed += float64(column)
+			} else {
+				tile.AddWater(column)
+				tile.Rain = intensity
+				rained += float64(column)
+			}
+
+			if intensity >= StormIntensity {
+				stormTiles++
+				stormX += x
+				stormZ += z
+			}
+		}
+	}
+
+	s.State.Quantities[chem.Water].Value += float32(rained)
+	s.State.Quantities[chem.Ice].Value += float32(snowed)
+	s.State.Quantities[chem.WaterVapour].Value -= float32(rained + snowed)
+
+	// Announce a storm when it starts, and let it pass once most of it has rained out
+	coverage := float32(stormTiles) / float32(s.Cfg.Simulation.Width*s.Cfg.Simulation.Depth)
+	if !s.storm && coverage >= StormCoverage {
+		s.storm = true
+		s.notify(fmt.Sprintf("A storm has started around (%d, %d)", stormX/stormTiles, stormZ/stormTiles))
+	} else if s.storm && coverage < StormCoverage/2 {
+		s.storm = false
+		s.notify("The storm has passed")
+	}
+}
+
+// Storm returns whether a storm is currently raining over the map
+func (s Simulation) Storm() bool {
+	return s.storm
+}
+
+// RainIntensity returns the intensity of the rain falling on the tile at x, z in mm/h
+func (s Simulation) RainIntensity(x, z int) float32 {
+	return s.tilemap[x][z].Rain
+}
+
+// Get the height of the atmosphere cell that the surface of a tile sits in
+func (s *Simulation) surfaceY(tile *entity.Tile) int {
+	y := int(tile.WorldY + entity.TileHeight)
+	return max(0, min(y, s.Cfg.Simulation.Height-1))
+}
@@ -0,0 +1,134 @@
+package sim
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cbeimers113/strands/internal/entity"
+)
+
+// The species of plants loaded from saves that were made before plants had species
+const UnknownSpecies = "Unknown"
+
+var (
+	// The chance per second that a mature plant drops a seed
+	SeedRate float32 = 1e-5
+
+	// How saturated the soil has to be for a seed to be sure of germinating on the most fertile ground, drier soil is less likely
+	GerminationSaturation float32 = 0.5
+
+	// The standing water, ice and snow in L on a tile that keeps seeds from taking root on it
+	SeedDrowning float32 = 20
+
+	// The most plants that can grow on one tile, seeds that land on a full tile don't take root
+	MaxPlants = 6
+)
+
+// A seed on its way to the tile it lands on
+type seed struct {
+	genome  entity.Genome
+	tile    *entity.Tile
+	species string // The species of the plant that dropped the seed
+}
+
+// Let the mature plants drop seeds over a number of seconds of simulated time. Each seed carries a mutated copy of its parent's genome,
+// crossed with another mature plant of the same species on the tile if there is one, and lands on a tile within the parent's
+// dispersal radius, where it germinates depending on how fertile and moist the ground is. The seedlings are reported once a day.
+func (s *Simulation) reproduce(seconds float32) {
+	rng := s.State.Rand
+
+	// Every seed is dropped before any germinate, so seedlings can't drop seeds of their own in the tick they sprout
+	var seeds []seed
+	for _, tile := range s.GetTiles() {
+		for _, plant := range tile.Plants {
+			if !plant.Mature() || plant.Burning || rng.Float32() >= SeedRate*seconds {
+				continue
+			}
+
+			genome := plant.Genome
+			if partner := pollinator(tile, plant); partner != nil {
+				genome = entity.Crossover(genome, partner.Genome, rng)
+			}
+
+			seeds = append(seeds, seed{genome: genome.Mutate(rng), tile: s.disperse(tile, plant.DispersalRadius), species: plant.Species})
+		}
+	}
+
+	for _, seed := range seeds {
+		if rng.Float32() >= germination(seed.tile) || !seed.tile.AddPlant(seed.genome) {
+			continue
+		}
+
+		// A new species is rare enough to be worth telling the player about straight away
+		if seed.genome.Species != seed.species {
+			s.notify(fmt.Sprintf("A new species, %s, branched off from %s at (%d, %d)", seed.genome.Species, seed.species, seed.tile.MapX, seed.tile.MapZ))
+		}
+
+		s.State.Births[seed.genome.Species]++
+		if s.sprouted == nil {
+			s.sprouted = make(map[string]int)
+		}
+		s.sprouted[seed.genome.Species]++
+	}
+
+	if day := s.State.Clock.Day; day != s.reported {
+		s.reportSeedlings()
+		s.reported = day
+	}
+}
+
+// Tell the player how many seedlings of each species sprouted since the last report, in one message
+func (s *Simulation) reportSeedlings() {
+	if len(s.sprouted) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(s.sprouted))
+	for name := range s.sprouted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	counts := make([]string, len(names))
+	for i, name := range names {
+		counts[i] = fmt.Sprintf("%d %s", s.sprouted[name], name)
+	}
+
+	s.notify("Seedlings sprouted yesterday: " + strings.Join(counts, ", "))
+	s.sprouted = nil
+}
+
+// Find another mature plant of the same species on a tile to cross a plant's seed with, or nil if there isn't one
+func pollinator(tile *entity.Tile, plant *entity.Plant) *entity.Plant {
+	for _, other := range tile.Plants {
+		if other != plant && other.Mature() && other.Species == plant.Species {
+			return other
+		}
+	}
+
+	return nil
+}
+
+// Carry a seed from a tile up to a number of tiles away, by stepping to random neighbours.
+// Seeds that would be carried off the edge of the map land on the last tile they reached.
+func (s *Simulation) disperse(tile *entity.Tile, radius int) *entity.Tile {
+	steps := s.State.Rand.Intn(max(1, radius) + 1)
+
+	for i := 0; i < steps; i++ {
+		if neighbour := tile.Neighbours[s.State.Rand.Intn(len(tile.Neighbours))]; neighbour != nil {
+			tile = neighbour
+		}
+	}
+
+	return tile
+}
+
+// Get the chance that a seed landing on a tile germinates, from how fertile and moist the ground is
+func germination(tile *entity.Tile) float32 {
+	if len(tile.Plants) >= MaxPlants || tile.WaterLevel.Value+tile.Ice.Value+tile.Snow.Value >= SeedDrowning {
+		return 0
+	}
+
+	return tile.Fertility() * min(1, tile.Saturation()/GerminationSaturation)
+}
@@ -0,0 +1,145 @@
+package sim
+
+import (
+	"github.com/g3n/engine/math32"
+
+	"cbeimers113/strands/internal/chem"
+	"cbeimers113/strands/internal/entity"
+)
+
+var (
+	// The fraction of the momentum of flowing water that's lost to friction with the ground per second
+	WaterFriction float32 = 0.5
+
+	// The cross section in m² of the virtual pipe that carries water between two neighbouring tiles
+	PipeArea float32 = 0.5
+)
+
+// The acceleration due to gravity in m/s²
+const gravity float32 = 9.81
+
+// The furthest a wave can travel across a tile per step, as a fraction of the distance between two tiles
+const maxWaveCourant float32 = 0.5
+
+// The shallowest water in L that the flow is measured through, thinner films of water would seem to move impossibly fast
+const minFlowDepth float32 = 10
+
+// The distance in metres between the centres of two neighbouring tiles
+var tileSpacing float32 = math32.Sin(math32.Pi / 3)
+
+// Flow water over the tiles with a shallow water model over a number of seconds of simulated time.
+// Each tile has a virtual pipe to each of its neighbours, and the difference in the height of the water's surface
+// accelerates the water through the pipe. The flow through the pipes carries over between ticks, so water keeps its
+// momentum, runs downhill in rivers and sloshes around in lakes.
+func (s *Simulation) flowShallowWater(seconds float32) {
+	s.allocateFlow()
+
+	// Split the tick into steps short enough that a wave on the deepest water can't cross more than part of a tile in one
+	tiles := s.GetTiles()
+	var deepest float32
+	for _, tile := range tiles {
+		deepest = max(deepest, chem.LitresToCubicMetres(tile.WaterLevel.Value))
+	}
+
+	steps := max(1, int(math32.Ceil(seconds*math32.Sqrt(gravity*deepest)/(maxWaveCourant*tileSpacing))))
+	step := seconds / float32(steps)
+
+	for i := 0; i < steps; i++ {
+		accelerateWater(tiles, step)
+		s.pipeWater(tiles, step)
+	}
+
+	measureFlow(tiles)
+}
+
+// Accelerate the water in each pipe by the difference in the height of the water at either end of it,
+// then scale back the pipes out of any tile that would lose more water than it holds
+func accelerateWater(tiles []*entity.Tile, seconds float32) {
+	drag := max(0, 1-WaterFriction*seconds)
+
+	for _, tile := range tiles {
+		elevation := waterSurface(tile)
+
+		var total float32
+		for i, neighbour := range tile.Neighbours {
+			tile.WaterFlux[i] *= drag
+
+			if neighbour != nil {
+				head := elevation - waterSurface(neighbour)
+				tile.WaterFlux[i] += chem.CubicMetresToLitres(seconds * PipeArea * gravity * head / tileSpacing)
+			}
+
+			tile.WaterFlux[i] = max(0, tile.WaterFlux[i])
+			total += tile.WaterFlux[i]
+		}
+
+		if total*seconds > tile.WaterLevel.Value {
+			scale := tile.WaterLevel.Value / (total * seconds)
+			for i := range tile.WaterFlux {
+				tile.WaterFlux[i] *= scale
+			}
+		}
+	}
+}
+
+// Move the water through every pipe at once, so that the order tiles are visited in doesn't matter
+func (s *Simulation) pipeWater(tiles []*entity.Tile, seconds float32) {
+	width := s.Cfg.Simulation.Width
+
+	for _, tile := range tiles {
+		for i, neighbour := range tile.Neighbours {
+			if neighbour == nil || tile.WaterFlux[i] <= 0 {
+				continue
+			}
+
+			amount := tile.WaterFlux[i] * seconds
+			s.outflow[tile.MapX+tile.MapZ*width][i] += amount
+			s.flow[tile.MapX+tile.MapZ*width] -= amount
+			s.flow[neighbour.MapX+neighbour.MapZ*width] += amount
+		}
+	}
+
+	for _, tile := range tiles {
+		i := tile.MapX + tile.MapZ*width
+		tile.AddWater(s.flow[i])
+		s.flow[i] = 0
+	}
+}
+
+// Work out the velocity of the water on each tile from the water flowing through its pipes
+func measureFlow(tiles []*entity.Tile) {
+	for _, tile := range tiles {
+		tile.Flow = math32.Vector3{}
+
+		if tile.WaterLevel.Value <= 0 {
+			continue
+		}
+
+		for i, neighbour := range tile.Neighbours {
+			if neighbour == nil {
+				continue
+			}
+
+			// Water flowing out towards the neighbour and water flowing in from it both move in the same direction
+			var inflow float32
+			for j, other := range neighbour.Neighbours {
+				if other == tile {
+					inflow = neighbour.WaterFlux[j]
+				}
+			}
+
+			direction := math32.Vector3{X: neighbour.WorldX() - tile.WorldX(), Z: neighbour.WorldZ() - tile.WorldZ()}
+			direction.Normalize()
+			tile.Flow.Add(direction.MultiplyScalar((tile.WaterFlux[i] - inflow) / 2))
+		}
+
+		// The water passes through a face as wide as the tile and as deep as the water
+		tile.Flow.DivideScalar(max(tile.WaterLevel.Value, minFlowDepth))
+	}
+}
+
+// Get the height in metres of the surface of the water on a tile, or of the tile itself if it's dry.
+// Ice on the tile lifts the water over it.
+func waterSurface(tile *entity.Tile) float32 {
+	return tile.WorldY + entity.TileHeight + chem.LitresToCubicMetres(tile.WaterLevel.Value+tile.Ice.Value)
+}
@@ -0,0 +1,363 @@
+package sim
+
+import (
+	"cbeimers113/strands/internal/atmosphere"
+	"cbeimers113/strands/internal/chem"
+	"cbeimers113/strands/internal/config"
+	"cbeimers113/strands/internal/entity"
+	"cbeimers113/strands/internal/state"
+)
+
+// The height in metres of the highest ground on a newly generated map
+var MaxElevation float32 = 4.0 / 3
+
+// Simulation is the headless core of the world: the tilemap, its plants and the atmosphere above it.
+// It has no knowledge of the scene graph; the renderer observes it and draws whatever it finds.
+type Simulation struct {
+	Cfg   *config.Config
+	State *state.State
+
+	tilemap    [][]*entity.Tile
+	atmosphere *atmosphere.Atmosphere
+
+	flow    []float32    // The change in water level of each tile this tick, indexed by x + z*width
+	outflow [][6]float32 // The water in L that flowed from each tile to each of its neighbours this tick, indexed by x + z*width
+	ticks   int          // How many ticks the simulation has been updated for since it was created or loaded
+	storm   bool         // Whether a storm is currently raining over the map
+	fire    bool         // Whether any plants on the map are burning
+	events  []string     // Messages about things that happened in the simulation, waiting to be shown to the player
+
+	sprouted map[string]int // How many seedlings of each species have sprouted since they were last reported
+	reported int            // The day that the sprouted seedlings were last reported on
+}
+
+// Create a fresh simulation
+func New(cfg *config.Config, st *state.State) *Simulation {
+	s := &Simulation{
+		Cfg:        cfg,
+		State:      st,
+		atmosphere: atmosphere.New(cfg),
+	}
+
+	s.createMap()
+	s.tally()
+
+	return s
+}
+
+// Load a simulation from saved tiles and atmosphere
+func Load(cfg *config.Config, st *state.State, tiles []*entity.Tile, cells []*state.Cell) *Simulation {
+	s := &Simulation{
+		Cfg:        cfg,
+		State:      st,
+		atmosphere: atmosphere.Load(cfg, cells),
+	}
+
+	s.loadMap(tiles)
+	s.tally()
+
+	return s
+}
+
+// Create the tilemap
+func (s *Simulation) createMap() {
+	heightmap, min, max := s.makeHeightmap()
+	s.makeTilemap(heightmap, min, max)
+	s.assignTileNeighbourhoods()
+	s.flood()
+	s.shapeAtmosphere()
+}
+
+// Check if a given coordinate is within the tilemap boundaries
+func (s *Simulation) inBounds(x, z int) bool {
+	return x >= 0 && x < s.Cfg.Simulation.Width && z >= 0 && z < s.Cfg.Simulation.Depth
+}
+
+// Generate a heightmap with the configured terrain generator, return the map and its min and max values
+func (s *Simulation) makeHeightmap() ([][]float32, float32, float32) {
+	var heightmap = make([][]float32, s.Cfg.Simulation.Width)
+	var min float32 = 1_000_000_000.0
+	var max float32 = -min
+
+	terrain := s.classicTerrain()
+	if s.Cfg.Terrain.Generator == config.Island {
+		terrain = s.islandTerrain()
+	}
+
+	for x := 0; x < s.Cfg.Simulation.Width; x++ {
+		heightmap[x] = make([]float32, s.Cfg.Simulation.Depth)
+
+		for z := 0; z < s.Cfg.Simulation.Depth; z++ {
+			height := terrain(x, z)
+			heightmap[x][z] = height
+
+			// Record min and max so that the tile types can be mapped to height range
+			if height < min {
+				min = height
+			}
+
+			if height > max {
+				max = height
+			}
+		}
+	}
+
+	return heightmap, min, max
+}
+
+// Create a tilemap with a given heightmap specification, with each tile taking on the biome its height and climate fall in
+func (s *Simulation) makeTilemap(heightmap [][]float32, min, max float32) {
+	width := s.Cfg.Simulation.Width
+	depth := s.Cfg.Simulation.Depth
+
+	s.tilemap = make([][]*entity.Tile, width)
+	climate := s.climate()
+	founders := make(map[string]entity.Genome)
+
+	for x := 0; x < width; x++ {
+
+		s.tilemap[x] = make([]*entity.Tile, depth)
+		for z := 0; z < depth; z++ {
+			elevation := (heightmap[x][z] - min) / (max - min)
+			height := elevation * MaxElevation
+			underwater := height+entity.TileHeight < s.Cfg.Water.SeaLevel
+
+			temperature, moisture := climate(x, z, elevation, underwater)
+			biome := chooseBiome(elevation, temperature, moisture)
+
+			// The biome chooses among the tile types that spawn at this height, keeping its own type if no type spawns here
+			tType, ok := entity.ChooseTileType(elevation, biome.Tile)
+			if !ok {
+				tType, _ = entity.TileTypeNamed(biome.Tile)
+			}
+
+			tile := entity.NewTile(x, z, height, biome.Temperature, biome.Water, tType, s.State.Rand)
+			tile.SoilMoisture.Value = tType.SoilCapacity * biome.Saturation
+			s.tilemap[x][z] = tile
+
+			// Plants don't take root under the sea
+			if !underwater {
+				s.plantBiome(tile, biome, founders)
+			}
+		}
+	}
+}
+
+// Load a tilemap from serialized tiles
+func (s *Simulation) loadMap(tiles []*entity.Tile) {
+	width := s.Cfg.Simulation.Width
+	depth := s.Cfg.Simulation.Depth
+
+	s.tilemap = make([][]*entity.Tile, width)
+
+	for x := 0; x < width; x++ {
+		s.tilemap[x] = make([]*entity.Tile, depth)
+
+		for z := 0; z < depth; z++ {
+			tile := tiles[x+z*width]
+			tile.Rand = s.State.Rand
+
+			if tType, ok := entity.TileTypeNamed(tile.Type.Name); ok {
+				tile.Type = tType
+			} else if tile.Type.Texture == "" {
+				// Saves from before tile types had textures drew each tile with the texture named after its type
+				tile.Type.Texture = tile.Type.Name
+			}
+
+			// Saves from before tiles had soil start out with dry soil, and with the topsoil of a newly spawned tile
+			if tile.SoilMoisture == nil {
+				tile.SoilMoisture = &chem.Quantity{Units: chem.Litre}
+			}
+
+			if tile.Topsoil == nil {
+				tile.Topsoil = entity.NewTopsoil(tile.Type)
+			}
+
+			// Saves from before water could freeze have no ice or snow on their tiles
+			if tile.Ice == nil {
+				tile.Ice = &chem.Quantity{Units: chem.Litre}
+			}
+
+			if tile.Snow == nil {
+				tile.Snow = &chem.Quantity{Units: chem.Litre}
+			}
+			s.tilemap[x][z] = tile
+
+			for _, plant := range tile.Plants {
+				plant.Rand = s.State.Rand
+
+				// Saves from before plants' ages were counted in seconds count the ticks they've lived for at today's tick length
+				if plant.Ticks > 0 {
+					plant.Age = float32(plant.Ticks) * s.State.Clock.TickSeconds()
+					plant.Ticks = 0
+				}
+
+				// Saves from before plants had a mass give them the mass that matches how tall they'd grown by their age
+				if plant.Biomass <= 0 {
+					plant.Biomass = entity.SproutBiomass + (entity.GrownBiomass-entity.SproutBiomass)*min(1, plant.Age/entity.MatureAge)
+					plant.Moisture = 1
+				}
+
+				// Saves from before plants reproduced count as one species, which scatters its seeds to the neighbouring tiles
+				if plant.Species == "" {
+					plant.Species = UnknownSpecies
+				}
+
+				if plant.DispersalRadius <= 0 {
+					plant.DispersalRadius = 1
+				}
+			}
+		}
+	}
+
+	s.assignTileNeighbourhoods()
+	s.shapeAtmosphere()
+}
+
+// Tell the atmosphere where the ground is so that the wind blows over the tiles instead of through them
+func (s *Simulation) shapeAtmosphere() {
+	for x := 0; x < s.Cfg.Simulation.Width; x++ {
+		for z := 0; z < s.Cfg.Simulation.Depth; z++ {
+			s.atmosphere.SetGround(x, z, s.surfaceY(s.tilemap[x][z]))
+		}
+	}
+}
+
+// Give each tile in a tilemap a list of pointers to its neighbours
+func (s *Simulation) assignTileNeighbourhoods() {
+	// Base hexmap neighbourhood offsets
+	nbOffsets := [][]int{
+		{1, 0},  // Right
+		{1, -1}, // Top right
+		{1, 1},  // Bottom right
+		{-1, 0}, // Left
+		{0, -1}, // Top left
+		{0, 1},  // Bottom left
+	}
+
+	// Assign each tile's neighbours
+	for x := 0; x < s.Cfg.Simulation.Width; x++ {
+		for z := 0; z < s.Cfg.Simulation.Depth; z++ {
+			var neighbours entity.Neighbourhood
+			tile := s.tilemap[x][z]
+
+			for i, offs := range nbOffsets {
+				xOffs := x + offs[0]
+				zOffs := z + offs[1]
+
+				// Stagger offsets on the x axis for every other row for "top/bottom" neighbours
+				if z%2 == 0 && i%3 != 0 {
+					xOffs--
+				}
+
+				if s.inBounds(xOffs, zOffs) {
+					neighbours[i] = s.tilemap[xOffs][zOffs]
+				}
+			}
+
+			tile.Neighbours = neighbours
+		}
+	}
+}
+
+// Advance the simulation by one tick. Every tick covers the same amount of simulated time,
+// so the outcome of a run doesn't depend on how fast the ticks happen. The wind and the shallow water move far faster
+// than anything else in the world, so they split the tick into as many short steps as they need to stay stable.
+func (s *Simulation) Update() {
+	seconds := s.State.Clock.TickSeconds()
+
+	s.blow(s.atmosphere.Update(seconds * 1000))
+	s.heat(seconds)
+	s.freeze()
+	s.exchangeWater(seconds)
+	s.precipitate(seconds)
+	s.soakWater(seconds)
+	s.transpire(seconds)
+	s.photosynthesise(seconds)
+	s.burn(seconds)
+
+	if s.Cfg.Water.ShallowWater {
+		s.flowShallowWater(seconds)
+	} else {
+		s.flowWater()
+	}
+
+	s.exchangeOcean(seconds)
+	s.erode(seconds)
+	s.transformTiles(seconds)
+	s.reproduce(seconds)
+
+	// Tiles and their plants are visited in map order so that a tick is reproducible for a given seed
+	for x := 0; x < s.Cfg.Simulation.Width; x++ {
+		for z := 0; z < s.Cfg.Simulation.Depth; z++ {
+			tile := s.tilemap[x][z]
+			tile.Update(seconds)
+
+			for _, plant := range tile.Plants {
+				plant.Update(seconds)
+			}
+		}
+	}
+
+	s.State.Clock.Update()
+
+	s.ticks++
+	if AuditInterval > 0 && s.ticks%AuditInterval == 0 {
+		s.audit()
+	}
+}
+
+// Add the air that the wind blew in over the edges of the map to the totals, or take away the air it blew out
+func (s *Simulation) blow(blown map[chem.ElementType]float32) {
+	for element, amount := range blown {
+		// Water in the air is counted separately from water on the surface
+		if element == chem.Water {
+			element = chem.WaterVapour
+		}
+
+		s.State.Quantities[element].Value += amount
+	}
+}
+
+// Record a message about something that happened in the simulation
+func (s *Simulation) notify(message string) {
+	s.events = append(s.events, message)
+}
+
+// Events returns the messages recorded since the last call, for the game to show to the player
+func (s *Simulation) Events() []string {
+	events := s.events
+	s.events = nil
+
+	return events
+}
+
+// GetAtmosphere returns a linear slice of Cells representing the atmosphere
+func (s Simulation) GetAtmosphere() []*state.Cell {
+	return s.atmosphere.GetCells()
+}
+
+// SetAtmosphere sets the cells in the atmosphere from a linear slice of Cells
+func (s *Simulation) SetAtmosphere(cells []*state.Cell) {
+	s.atmosphere.SetCells(cells)
+}
+
+// GetTiles returns a linear slice Tiles representing the map
+func (s Simulation) GetTiles() []*entity.Tile {
+	width := s.Cfg.Simulation.Width
+	depth := s.Cfg.Simulation.Depth
+	t := make([]*entity.Tile, width*depth)
+
+	for x := 0; x < width; x++ {
+		for z := 0; z < depth; z++ {
+			t[x+z*width] = s.tilemap[x][z]
+		}
+	}
+
+	return t
+}
+
+// GetTile returns the tile at x, z
+func (s Simulation) GetTile(x, z int) *entity.Tile {
+	return s.tilemap[x][z]
+}
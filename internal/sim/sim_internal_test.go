@@ -0,0 +1,1139 @@
+package sim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/g3n/engine/math32"
+	"github.com/stretchr/testify/assert"
+
+	"cbeimers113/strands/internal/chem"
+	"cbeimers113/strands/internal/config"
+	"cbeimers113/strands/internal/entity"
+	"cbeimers113/strands/internal/state"
+)
+
+// Create a small config so that tests don't depend on the user's config file
+func testConfig() *config.Config {
+	cfg := &config.Config{Name: "Strands Test"}
+	cfg.Simulation.Width = 8
+	cfg.Simulation.Height = 4
+	cfg.Simulation.Depth = 8
+	cfg.Simulation.Speed = 24
+	cfg.Simulation.TickLength = 12
+
+	return cfg
+}
+
+func Test_Update(t *testing.T) {
+	tests := []struct {
+		name  string
+		ticks int
+		speed int // The sim speed of the second simulation, if different from the first
+	}{
+		{
+			name:  "Happy path - no ticks",
+			ticks: 0,
+		},
+		{
+			name:  "Happy path - many ticks",
+			ticks: 50,
+		},
+		{
+			name:  "Happy path - the sim speed doesn't change the outcome",
+			ticks: 50,
+			speed: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			other := *cfg
+			if tt.speed > 0 {
+				other.Simulation.Speed = tt.speed
+			}
+
+			a := New(cfg, state.New(cfg, 42))
+			b := New(&other, state.New(&other, 42))
+
+			// Make sure the plant can take root regardless of what the seed generated
+			for _, s := range []*Simulation{a, b} {
+				s.GetTile(3, 3).Type = entity.Grass
+				s.GetTile(3, 3).AddPlant(entity.RandomGenome(s.State.Rand))
+			}
+
+			for i := 0; i < tt.ticks; i++ {
+				a.Update()
+				b.Update()
+			}
+
+			// Two simulations with the same seed must stay identical without a renderer
+			for i, tile := range a.GetTiles() {
+				other := b.GetTiles()[i]
+				assert.Equal(t, tile.WaterLevel.Value, other.WaterLevel.Value)
+				assert.Equal(t, len(tile.Plants), len(other.Plants))
+			}
+			assert.Equal(t, a.State.Clock.String(), b.State.Clock.String())
+
+			if tt.ticks > 0 {
+				assert.Equal(t, float32(tt.ticks)*a.State.Clock.TickSeconds(), a.GetTile(3, 3).Plants[0].Age)
+			}
+		})
+	}
+}
+
+// Time a tick on the default map, which has to be cheap enough to run on the render goroutine many times a second
+func Benchmark_Update(b *testing.B) {
+	cfg := config.Default()
+	s := New(cfg, state.New(cfg, 1))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Update()
+	}
+}
+
+func Test_Load(t *testing.T) {
+	tests := []struct {
+		name    string
+		plant   string  // The plant as it was saved
+		age     float32 // The age in seconds the plant should load with
+		biomass float32 // The dry mass in kg the plant should load with
+	}{
+		{
+			name:    "Happy path - a plant keeps the age and mass it was saved with",
+			plant:   `{"species": "Kaloria", "age_secs": 60000, "biomass": 0.003, "moisture": 0.5}`,
+			age:     60000,
+			biomass: 0.003,
+		},
+		{
+			name:    "Happy path - a plant saved before plants had a mass is as heavy as a grown plant once it's mature",
+			plant:   `{"age": 20000}`,
+			age:     240000,
+			biomass: entity.GrownBiomass,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			st := state.New(cfg, 7)
+			s := New(cfg, st)
+
+			var plant entity.Plant
+			assert.NoError(t, json.Unmarshal([]byte(tt.plant), &plant))
+
+			tiles := s.GetTiles()
+			for _, tile := range tiles {
+				tile.Plants = nil
+			}
+			tiles[0].Plants = []*entity.Plant{&plant}
+
+			loaded := Load(cfg, st, tiles, s.GetAtmosphere()).GetTiles()[0].Plants[0]
+			assert.Equal(t, tt.age, loaded.Age)
+			assert.InDelta(t, tt.biomass, loaded.Biomass, 1e-6)
+			assert.Zero(t, loaded.Ticks)
+		})
+	}
+}
+
+func Test_exchangeWater(t *testing.T) {
+	tests := []struct {
+		name     string
+		humidity float32 // Starting humidity of every cell as a fraction of saturation
+		evap     bool    // Whether water should move from the tiles into the air
+	}{
+		{
+			name:     "Happy path - dry air takes up water",
+			humidity: 0,
+			evap:     true,
+		},
+		{
+			name:     "Happy path - supersaturated air condenses",
+			humidity: 3,
+			evap:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 7))
+
+			for _, cell := range s.GetAtmosphere() {
+				cell.Quantities[chem.Water].Value = tt.humidity * chem.SaturationHumidity(cell.Temperature)
+			}
+			s.tally()
+
+			totals := func() (surface, vapour float64) {
+				for _, tile := range s.GetTiles() {
+					surface += float64(tile.WaterLevel.Value)
+				}
+
+				for _, cell := range s.GetAtmosphere() {
+					vapour += float64(cell.Quantities[chem.Water].Value)
+				}
+
+				return
+			}
+
+			surface, vapour := totals()
+			for i := 0; i < 100; i++ {
+				s.exchangeWater(60)
+			}
+			gotSurface, gotVapour := totals()
+
+			// Water only changes pools, and the tracked totals follow it
+			assert.InDelta(t, surface+vapour, gotSurface+gotVapour, 1e-2)
+			assert.InDelta(t, gotSurface, s.State.Quantities[chem.Water].Value, 1e-1)
+			assert.InDelta(t, gotVapour, s.State.Quantities[chem.WaterVapour].Value, 1e-2)
+			assert.Equal(t, tt.evap, gotVapour > vapour)
+			assert.Equal(t, tt.evap, gotSurface < surface)
+		})
+	}
+}
+
+func Test_precipitate(t *testing.T) {
+	tests := []struct {
+		name     string
+		humidity float32 // Starting humidity of every cell as a fraction of saturation
+		storm    bool    // Whether a storm should start
+	}{
+		{
+			name:     "Happy path - saturated columns rain",
+			humidity: 3,
+			storm:    true,
+		},
+		{
+			name:     "Happy path - unsaturated columns stay dry",
+			humidity: 0.5,
+			storm:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 7))
+
+			for _, cell := range s.GetAtmosphere() {
+				cell.Quantities[chem.Water].Value = tt.humidity * chem.SaturationHumidity(cell.Temperature)
+			}
+			s.tally()
+
+			surface := s.State.Quantities[chem.Water].Value
+			vapour := s.State.Quantities[chem.WaterVapour].Value
+			s.precipitate(60)
+
+			// Rain only moves water out of the air and onto the tiles
+			var raining bool
+			for _, tile := range s.GetTiles() {
+				raining = raining || tile.Rain > 0
+			}
+
+			assert.Equal(t, tt.storm, raining)
+			assert.Equal(t, tt.storm, s.Storm())
+			assert.Equal(t, tt.storm, len(s.Events()) == 1)
+			assert.InDelta(t, surface+vapour, s.State.Quantities[chem.Water].Value+s.State.Quantities[chem.WaterVapour].Value, 1e-2)
+		})
+	}
+}
+
+func Test_heat(t *testing.T) {
+	tests := []struct {
+		name  string
+		hour  int
+		warms bool // Whether the tiles should warm up
+	}{
+		{
+			name:  "Happy path - tiles warm at noon",
+			hour:  12,
+			warms: true,
+		},
+		{
+			name:  "Happy path - tiles cool at midnight",
+			hour:  0,
+			warms: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 7))
+			s.State.Clock.SetTime(tt.hour, 0)
+
+			sand, stone := s.GetTile(1, 1), s.GetTile(5, 5)
+			sand.Type, stone.Type = entity.Sand, entity.Stone
+
+			for _, tile := range []*entity.Tile{sand, stone} {
+				tile.Temperature.Value = 22
+				tile.WaterLevel.Value = 0
+			}
+
+			s.heat(600)
+
+			// Sand changes temperature faster than stone
+			assert.Equal(t, tt.warms, sand.Temperature.Value > 22)
+			assert.Equal(t, tt.warms, stone.Temperature.Value > 22)
+			assert.Greater(t, math32.Abs(sand.Temperature.Value-22), math32.Abs(stone.Temperature.Value-22))
+		})
+	}
+}
+
+func Test_Audit(t *testing.T) {
+	tests := []struct {
+		name   string
+		ticks  int
+		tamper float32 // Litres added to the recorded surface water without adding them to any tile
+		drifts int
+	}{
+		{
+			name:  "Happy path - totals are conserved",
+			ticks: 50,
+		},
+		{
+			name:   "Sad path - recorded total drifts",
+			ticks:  10,
+			tamper: 1000,
+			drifts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interval := AuditInterval
+			AuditInterval = 0
+			defer func() { AuditInterval = interval }()
+
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 42))
+
+			for i := 0; i < tt.ticks; i++ {
+				s.Update()
+			}
+			s.State.Quantities[chem.Water].Value += tt.tamper
+
+			assert.Len(t, s.Audit(), tt.drifts)
+
+			// The audit corrects the totals it reports
+			assert.Empty(t, s.Audit())
+		})
+	}
+}
+
+func Test_flowWater(t *testing.T) {
+	tests := []struct {
+		name       string
+		x, z       int
+		water      float32
+		tickLength int // The simulated seconds in a tick
+		waterTick  int // The ticks since the source last spread its water
+		spreads    bool
+	}{
+		{
+			name:       "Happy path - even row",
+			x:          4,
+			z:          4,
+			water:      100,
+			tickLength: 12,
+			waterTick:  11,
+			spreads:    true,
+		},
+		{
+			name:       "Happy path - odd row",
+			x:          3,
+			z:          5,
+			water:      100,
+			tickLength: 12,
+			waterTick:  11,
+			spreads:    true,
+		},
+		{
+			name:       "Happy path - long ticks spread after fewer ticks",
+			x:          4,
+			z:          4,
+			water:      100,
+			tickLength: 60,
+			waterTick:  3,
+			spreads:    true,
+		},
+		{
+			name:       "Sad path - too soon since the last spread",
+			x:          4,
+			z:          4,
+			water:      100,
+			tickLength: 12,
+			waterTick:  5,
+			spreads:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Simulation.TickLength = tt.tickLength
+			s := New(cfg, state.New(cfg, 42))
+
+			// Flatten and dry the map so that only the water poured on one tile can flow
+			for _, tile := range s.GetTiles() {
+				tile.WorldY = 0
+				tile.WaterLevel.Value = 0
+				tile.WaterTick = 0
+			}
+
+			source := s.GetTile(tt.x, tt.z)
+			source.WaterLevel.Value = tt.water
+			source.WaterTick = tt.waterTick
+			s.flowWater()
+
+			if !tt.spreads {
+				assert.Equal(t, tt.water, source.WaterLevel.Value)
+				assert.Equal(t, tt.waterTick, source.WaterTick)
+				return
+			}
+
+			// The water spreads evenly between the source and all of its neighbours, and no further
+			total := source.WaterLevel.Value
+			for _, neighbour := range source.Neighbours {
+				assert.InDelta(t, source.WaterLevel.Value, neighbour.WaterLevel.Value, 1e-3)
+				total += neighbour.WaterLevel.Value
+			}
+
+			assert.InDelta(t, tt.water, total, 1e-3)
+			assert.Zero(t, source.WaterTick)
+		})
+	}
+}
+
+func Test_flowShallowWater(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds float32 // How long the water flows for
+		moving  bool    // Whether the water should still be flowing afterwards
+	}{
+		{
+			name:    "Happy path - water rushes outwards",
+			seconds: 0.25,
+			moving:  true,
+		},
+		{
+			name:    "Happy path - water comes to rest",
+			seconds: 120,
+			moving:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 42))
+
+			// Flatten and dry the map, then pour a column of water onto one tile
+			for _, tile := range s.GetTiles() {
+				tile.WorldY = 0
+				tile.WaterLevel.Value = 0
+			}
+
+			source := s.GetTile(4, 4)
+			source.WaterLevel.Value = 500
+
+			for elapsed := float32(0); elapsed < tt.seconds; elapsed += 1.0 / 24 {
+				s.flowShallowWater(1.0 / 24)
+			}
+
+			var total, fastest float32
+			for _, tile := range s.GetTiles() {
+				total += tile.WaterLevel.Value
+				fastest = max(fastest, tile.Flow.Length())
+			}
+
+			// Water is neither created nor destroyed, and spreads the same way to the left and the right
+			assert.InDelta(t, 500, total, 1e-2)
+			assert.InDelta(t, source.Neighbours[0].WaterLevel.Value, source.Neighbours[3].WaterLevel.Value, 1e-2)
+			assert.Equal(t, tt.moving, fastest > 0.01)
+		})
+	}
+}
+
+func Test_soakWater(t *testing.T) {
+	tests := []struct {
+		name     string
+		tType    entity.TileType
+		water    float32 // Standing water on every tile to begin with
+		moisture float32 // Water in the soil of every tile to begin with
+		soaks    bool    // Whether water should soak into the soil
+	}{
+		{
+			name:  "Happy path - sand soaks up water",
+			tType: entity.Sand,
+			water: 100,
+			soaks: true,
+		},
+		{
+			name:  "Sad path - stone doesn't soak up water",
+			tType: entity.Stone,
+			water: 100,
+		},
+		{
+			name:     "Sad path - saturated soil doesn't soak up water",
+			tType:    entity.Dirt,
+			water:    100,
+			moisture: entity.Dirt.SoilCapacity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 42))
+
+			for _, tile := range s.GetTiles() {
+				tile.Type = tt.tType
+				tile.WaterLevel.Value = tt.water
+				tile.SoilMoisture.Value = tt.moisture
+			}
+
+			// Wet one tile's soil more than the rest, so that it seeps out to its neighbours
+			wet := s.GetTile(4, 4)
+			wet.SoilMoisture.Value = tt.tType.SoilCapacity
+			s.tally()
+
+			for i := 0; i < 100; i++ {
+				s.soakWater(10)
+			}
+
+			assert.Equal(t, tt.soaks, s.GetTile(0, 0).SoilMoisture.Value > tt.moisture)
+			assert.Equal(t, tt.soaks, wet.Neighbours[0].SoilMoisture.Value > s.GetTile(0, 0).SoilMoisture.Value)
+			assert.Empty(t, s.Audit())
+
+			for _, tile := range s.GetTiles() {
+				assert.LessOrEqual(t, tile.SoilMoisture.Value, tt.tType.SoilCapacity+1e-3)
+			}
+		})
+	}
+}
+
+func Test_transpire(t *testing.T) {
+	cfg := testConfig()
+	s := New(cfg, state.New(cfg, 42))
+
+	tile := s.GetTile(3, 3)
+	tile.Type = entity.Grass
+	tile.AddPlant(entity.RandomGenome(s.State.Rand))
+	tile.WaterLevel.Value = 100
+	tile.SoilMoisture.Value = 1
+	s.tally()
+
+	// Plants draw water out of the soil, never from the water standing on top of it
+	s.transpire(60)
+	assert.Less(t, tile.SoilMoisture.Value, float32(1))
+	assert.Equal(t, float32(100), tile.WaterLevel.Value)
+	assert.Empty(t, s.Audit())
+}
+
+func Test_erode(t *testing.T) {
+	tests := []struct {
+		name     string
+		tType    entity.TileType
+		outflow  float32 // Litres of water flowing out of the tile towards its right neighbour
+		sediment float32 // Sediment carried by the water on the tile to begin with
+		worn     bool    // Whether the tile should be worn away
+		becomes  entity.TileType
+	}{
+		{
+			name:    "Happy path - flowing water wears away dirt",
+			tType:   entity.Dirt,
+			outflow: 1000,
+			worn:    true,
+			becomes: entity.Dirt,
+		},
+		{
+			name:     "Happy path - still water lays down silt",
+			tType:    entity.Stone,
+			sediment: 1,
+			becomes:  entity.Sand,
+		},
+		{
+			name:    "Happy path - worn away topsoil exposes the bedrock",
+			tType:   entity.Grass,
+			outflow: 1_000_000,
+			worn:    true,
+			becomes: entity.Stone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 42))
+
+			tile := s.GetTile(4, 4)
+			tile.Type = tt.tType
+			tile.Topsoil = entity.NewTopsoil(tt.tType)
+			tile.Sediment = tt.sediment
+			tile.WorldY = 1
+			tile.Neighbours[0].WorldY = 0.5
+			height := tile.WorldY
+
+			// The ground that's worn away is carried along by the water, so none of it is lost
+			volume := func() (total float32) {
+				for _, tile := range s.GetTiles() {
+					total += tile.WorldY + tile.Sediment
+				}
+
+				return
+			}
+
+			before := volume()
+			for i := 0; i < 100; i++ {
+				s.allocateFlow()
+				s.outflow[4+4*cfg.Simulation.Width][0] = tt.outflow
+				s.erode(10)
+			}
+
+			assert.Equal(t, tt.worn, tile.WorldY < height)
+			assert.Equal(t, tt.becomes, tile.Type)
+			assert.InDelta(t, before, volume(), 1e-3)
+		})
+	}
+}
+
+func Test_exchangeOcean(t *testing.T) {
+	tests := []struct {
+		name   string
+		height float32 // The height of every tile
+		water  float32 // The water on every tile after the ocean has flooded the map
+		walled bool    // Whether the hollow in the middle of the map is walled off from the ocean
+		floods bool    // Whether the hollow in the middle of the map should flood
+	}{
+		{
+			name:   "Happy path - low ground floods up to sea level",
+			height: 0,
+			water:  200,
+			floods: true,
+		},
+		{
+			name:   "Sad path - a walled off hollow stays dry",
+			height: 0,
+			water:  200,
+			walled: true,
+		},
+		{
+			name:   "Sad path - ground above sea level drains into the ocean",
+			height: 1,
+			water:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Water.SeaLevel = 0.7
+			s := New(cfg, state.New(cfg, 42))
+
+			for _, tile := range s.GetTiles() {
+				tile.WorldY = tt.height
+				tile.WaterLevel.Value = 50
+			}
+
+			hollow := s.GetTile(4, 4)
+			hollow.WorldY = 0
+			hollow.WaterLevel.Value = 0
+			if tt.walled {
+				for _, neighbour := range hollow.Neighbours {
+					neighbour.WorldY = 1
+				}
+			}
+
+			s.flood()
+			s.tally()
+
+			for i := 0; i < 100; i++ {
+				s.exchangeOcean(10)
+			}
+
+			assert.InDelta(t, tt.water, s.GetTile(0, 0).WaterLevel.Value, 1e-3)
+			assert.Equal(t, tt.floods, hollow.WaterLevel.Value > 0)
+			assert.Empty(t, s.Audit())
+		})
+	}
+}
+
+func Test_islandTerrain(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator config.Generator
+		island    bool // Whether the land should be surrounded by ocean
+	}{
+		{
+			name:      "Happy path - an island is surrounded by ocean",
+			generator: config.Island,
+			island:    true,
+		},
+		{
+			name:      "Sad path - classic terrain reaches the edges of the map",
+			generator: config.Classic,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			cfg.Simulation.Width = 32
+			cfg.Simulation.Depth = 32
+			cfg.Water.SeaLevel = 0.7
+			cfg.Terrain.Generator = tt.generator
+			cfg.Terrain.Octaves = 4
+			cfg.Terrain.Roughness = 0.5
+			cfg.Terrain.Coastline = 0.5
+			s := New(cfg, state.New(cfg, 42))
+
+			var land, coast int
+			for _, tile := range s.GetTiles() {
+				if s.seaWater(tile) > 0 {
+					continue
+				}
+
+				land++
+				if isCoastal(tile) {
+					coast++
+				}
+			}
+
+			assert.Positive(t, land)
+			assert.Equal(t, tt.island, coast == 0)
+		})
+	}
+}
+
+func Test_chooseBiome(t *testing.T) {
+	tests := []struct {
+		name        string
+		elevation   float32
+		temperature float32
+		moisture    float32
+		biome       Biome
+	}{
+		{
+			name:        "Happy path - hot and dry ground is desert",
+			elevation:   0.5,
+			temperature: 30,
+			moisture:    0.1,
+			biome:       Desert,
+		},
+		{
+			name:        "Happy path - mild ground is grassland",
+			elevation:   0.5,
+			temperature: 20,
+			moisture:    0.45,
+			biome:       Grassland,
+		},
+		{
+			name:        "Happy path - wet ground is forest",
+			elevation:   0.5,
+			temperature: 20,
+			moisture:    0.6,
+			biome:       Forest,
+		},
+		{
+			name:        "Happy path - low and wet ground is marsh",
+			elevation:   0.1,
+			temperature: 20,
+			moisture:    0.9,
+			biome:       Marsh,
+		},
+		{
+			name:        "Happy path - cold ground is tundra however wet it is",
+			elevation:   0.1,
+			temperature: 0,
+			moisture:    0.9,
+			biome:       Tundra,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.biome, chooseBiome(tt.elevation, tt.temperature, tt.moisture))
+		})
+	}
+}
+
+func Test_makeTilemap(t *testing.T) {
+	// A custom type that takes over the low ground from grass
+	clay := entity.TileType{Name: "clay", Fertility: 0.7, Texture: "clay", HeatCapacity: 300_000, SoilCapacity: 500, SpawnBand: [2]float32{0, 0.5}}
+	grass := entity.Grass
+	grass.SpawnBand = [2]float32{0.5, 1}
+
+	tileTypes := entity.TileTypes
+	entity.TileTypes = []entity.TileType{entity.Sand, entity.Dirt, grass, entity.Stone, clay}
+	defer func() { entity.TileTypes = tileTypes }()
+
+	cfg := testConfig()
+	cfg.Simulation.Width, cfg.Simulation.Depth = 24, 24
+	s := New(cfg, state.New(cfg, 5))
+
+	counts := make(map[string]int)
+	for _, tile := range s.GetTiles() {
+		counts[tile.Type.Name]++
+	}
+
+	assert.Positive(t, counts[clay.Name])
+	assert.Positive(t, counts[grass.Name])
+}
+
+func Test_transformTiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		tType      entity.TileType
+		saturation float32 // How saturated the tile's soil stays
+		plants     int     // How many plants grow on the tile
+		days       float32 // How many days the conditions hold for
+		want       entity.TileType
+	}{
+		{
+			name:       "Happy path - moist dirt with plants grows grass",
+			tType:      entity.Dirt,
+			saturation: 0.6,
+			plants:     2,
+			days:       3,
+			want:       entity.Grass,
+		},
+		{
+			name:       "Sad path - moist dirt without plants stays dirt",
+			tType:      entity.Dirt,
+			saturation: 0.6,
+			days:       3,
+			want:       entity.Dirt,
+		},
+		{
+			name:       "Sad path - moist dirt with plants needs time to grow grass",
+			tType:      entity.Dirt,
+			saturation: 0.6,
+			plants:     2,
+			days:       1,
+			want:       entity.Dirt,
+		},
+		{
+			name:       "Happy path - grass in a drought dies back to dirt",
+			tType:      entity.Grass,
+			saturation: 0.05,
+			days:       3,
+			want:       entity.Dirt,
+		},
+		{
+			name:       "Happy path - soaked sand turns to mud",
+			tType:      entity.Sand,
+			saturation: 1,
+			days:       1,
+			want:       entity.Mud,
+		},
+		{
+			name:       "Happy path - dried out mud turns back to sand",
+			tType:      entity.Mud,
+			saturation: 0.1,
+			days:       2,
+			want:       entity.Sand,
+		},
+		{
+			name:  "Sad path - stone never changes",
+			tType: entity.Stone,
+			days:  10,
+			want:  entity.Stone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 42))
+			tile := s.GetTile(4, 4)
+			tile.Type = tt.tType
+			tile.Plants = nil
+
+			for i := 0; i < tt.plants; i++ {
+				tile.Plants = append(tile.Plants, entity.NewRandomPlant(s.State.Rand))
+			}
+
+			// Step an hour at a time, keeping the soil as saturated as the conditions call for
+			for hour := 0; hour < int(tt.days*24); hour++ {
+				tile.SoilMoisture.Value = tt.saturation * tile.Type.SoilCapacity
+				s.transformTiles(day / 24)
+			}
+
+			assert.Equal(t, tt.want, tile.Type)
+		})
+	}
+}
+
+func Test_freeze(t *testing.T) {
+	tests := []struct {
+		name        string
+		temperature float32
+		water       float32
+		ice         float32
+		snow        float32
+		wantWater   bool // Whether the tile should have liquid water left on it
+		wantIce     bool // Whether the tile should have ice left on it
+		wantSnow    bool // Whether the tile should have snow left on it
+	}{
+		{
+			name:        "Happy path - water below freezing turns to ice",
+			temperature: -5,
+			water:       3,
+			wantIce:     true,
+		},
+		{
+			name:        "Happy path - snow melts before the ice under it",
+			temperature: 2,
+			ice:         50,
+			snow:        1,
+			wantWater:   true,
+			wantIce:     true,
+		},
+		{
+			name:        "Sad path - ice below freezing stays frozen",
+			temperature: -5,
+			ice:         50,
+			snow:        1,
+			wantIce:     true,
+			wantSnow:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 7))
+
+			tile := s.GetTile(3, 3)
+			tile.Temperature.Value = tt.temperature
+			tile.WaterLevel.Value = tt.water
+			tile.Ice.Value = tt.ice
+			tile.Snow.Value = tt.snow
+			s.tally()
+
+			total := s.State.Quantities[chem.Water].Value + s.State.Quantities[chem.Ice].Value
+			s.freeze()
+
+			// Freezing and melting only move water between the surface and the ice, and bring the tile towards 0°C
+			assert.Equal(t, tt.wantWater, tile.WaterLevel.Value > 0)
+			assert.Equal(t, tt.wantIce, tile.Ice.Value > 0)
+			assert.Equal(t, tt.wantSnow, tile.Snow.Value > 0)
+			assert.LessOrEqual(t, math32.Abs(tile.Temperature.Value), math32.Abs(tt.temperature))
+			assert.InDelta(t, total, s.State.Quantities[chem.Water].Value+s.State.Quantities[chem.Ice].Value, 1e-2)
+		})
+	}
+}
+
+func Test_burn(t *testing.T) {
+	tests := []struct {
+		name     string
+		water    float32 // The standing water on both tiles
+		moisture float32 // The moisture of the plant next to the fire
+		ignites  bool    // Whether the plant on the fire's tile can be set alight
+		spreads  bool    // Whether the fire should spread to the plant next to it
+		burnsOut bool    // Whether the burning plant should burn away
+	}{
+		{
+			name:     "Happy path - fire spreads to a dry plant and leaves ash",
+			moisture: 0,
+			ignites:  true,
+			spreads:  true,
+			burnsOut: true,
+		},
+		{
+			name:     "Happy path - a well watered plant doesn't catch",
+			moisture: 1,
+			ignites:  true,
+			spreads:  false,
+			burnsOut: true,
+		},
+		{
+			name:     "Sad path - a plant on flooded ground can't be set alight, and goes out if it was",
+			water:    50,
+			moisture: 0,
+			ignites:  false,
+			spreads:  false,
+			burnsOut: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 7))
+
+			for _, tile := range s.GetTiles() {
+				tile.Plants = nil
+				tile.WaterLevel.Value, tile.Ice.Value, tile.Snow.Value = 0, 0, 0
+			}
+
+			source, target := s.GetTile(3, 3), s.GetTile(4, 3)
+			for _, tile := range []*entity.Tile{source, target} {
+				tile.Type = entity.Grass
+				tile.AddPlant(entity.RandomGenome(s.State.Rand))
+				tile.WaterLevel.Value = tt.water
+				s.cellAbove(tile).Wind = math32.Vector3{}
+			}
+
+			assert.Equal(t, tt.ignites, source.Ignite())
+			source.Plants[0].Burning = true
+			target.Plants[0].Moisture = tt.moisture
+			s.tally()
+
+			carbon := s.State.Quantities[chem.CarbonDioxide].Value
+			for i := 0; i < 100; i++ {
+				s.burn(12)
+			}
+
+			spread := len(target.Plants) == 0 || target.Plants[0].Burning
+			assert.Equal(t, tt.spreads, spread)
+			assert.Equal(t, tt.burnsOut, len(source.Plants) == 0)
+			assert.Equal(t, tt.burnsOut, source.Ash > 0)
+			assert.Equal(t, tt.burnsOut, s.State.Quantities[chem.CarbonDioxide].Value > carbon)
+			assert.Empty(t, s.Audit())
+		})
+	}
+}
+
+func Test_reproduce(t *testing.T) {
+	tests := []struct {
+		name   string
+		water  float32 // The standing water on every tile
+		plants int     // The plants already on every tile besides the parent
+		births bool    // Whether any seeds should germinate
+	}{
+		{
+			name:   "Happy path - seeds sprout around a mature plant",
+			births: true,
+		},
+		{
+			name:   "Sad path - seeds don't sprout on flooded ground",
+			water:  50,
+			births: false,
+		},
+		{
+			name:   "Sad path - seeds don't sprout on crowded ground",
+			plants: MaxPlants,
+			births: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seedRate := SeedRate
+			SeedRate = 1
+			defer func() { SeedRate = seedRate }()
+
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 7))
+
+			genome := entity.RandomGenome(s.State.Rand)
+			genome.DispersalRadius = 2
+
+			for _, tile := range s.GetTiles() {
+				tile.Plants = nil
+				tile.Type = entity.Grass
+				tile.Ash = 1
+				tile.SoilMoisture.Value = tile.Type.SoilCapacity
+				tile.WaterLevel.Value, tile.Ice.Value, tile.Snow.Value = tt.water, 0, 0
+
+				for i := 0; i < tt.plants; i++ {
+					tile.AddPlant(genome)
+				}
+			}
+
+			parent := s.GetTile(5, 5)
+			parent.AddPlant(genome)
+			parent.Plants[len(parent.Plants)-1].Age = entity.MatureAge
+
+			for i := 0; i < 5; i++ {
+				s.reproduce(1)
+			}
+
+			// Seedlings only sprout within the parent's dispersal radius
+			var births int
+			for _, tile := range s.GetTiles() {
+				sprouted := len(tile.Plants) - tt.plants
+				if tile == parent {
+					sprouted--
+				}
+
+				if sprouted > 0 {
+					assert.LessOrEqual(t, max(tile.MapX-parent.MapX, parent.MapX-tile.MapX), genome.DispersalRadius)
+					assert.LessOrEqual(t, max(tile.MapZ-parent.MapZ, parent.MapZ-tile.MapZ), genome.DispersalRadius)
+				}
+				births += sprouted
+			}
+
+			assert.Equal(t, tt.births, births > 0)
+			assert.Equal(t, births, s.State.Births[genome.Species])
+
+			// The seedlings are reported together once the day is over
+			assert.Empty(t, s.Events())
+			s.State.Clock.Day++
+			s.reproduce(0)
+			assert.Equal(t, tt.births, len(s.Events()) == 1)
+		})
+	}
+}
+
+func Test_photosynthesise(t *testing.T) {
+	tests := []struct {
+		name     string
+		hour     int
+		moisture float32 // The water in the soil under the plant, as a fraction of what it can hold
+		grows    bool    // Whether the plant should gain mass and give off oxygen
+	}{
+		{
+			name:     "Happy path - a plant in the sun grows and gives off oxygen",
+			hour:     12,
+			moisture: 1,
+			grows:    true,
+		},
+		{
+			name:     "Happy path - a plant in the dark wastes away",
+			hour:     0,
+			moisture: 1,
+			grows:    false,
+		},
+		{
+			name:     "Sad path - a plant on dry ground can't grow",
+			hour:     12,
+			moisture: 0,
+			grows:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			s := New(cfg, state.New(cfg, 7))
+			s.State.Clock.SetTime(tt.hour, 0)
+
+			for _, tile := range s.GetTiles() {
+				tile.Plants = nil
+			}
+
+			tile := s.GetTile(3, 3)
+			tile.Type = entity.Grass
+			tile.SoilMoisture.Value = tt.moisture * tile.Type.SoilCapacity
+			tile.AddPlant(entity.RandomGenome(s.State.Rand))
+			plant := tile.Plants[0]
+			s.tally()
+
+			oxygen := s.State.Quantities[chem.Oxygen].Value
+			carbon := s.State.Quantities[chem.CarbonDioxide].Value
+			for i := 0; i < 10; i++ {
+				s.photosynthesise(12)
+			}
+
+			assert.Equal(t, tt.grows, plant.Biomass > entity.SproutBiomass)
+			assert.Equal(t, tt.grows, s.State.Quantities[chem.Oxygen].Value > oxygen)
+			assert.Equal(t, tt.grows, s.State.Quantities[chem.CarbonDioxide].Value < carbon)
+			assert.Empty(t, s.Audit())
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package sim
+
+import (
+	"math"
+
+	"github.com/aquilax/go-perlin"
+)
+
+const (
+	// The size in tiles of the broadest hills and valleys on an island, finer layers of noise are smaller
+	islandScale = 16
+
+	// The size in tiles of the widest bays and headlands along an island's coast
+	coastScale = 8
+
+	// How far from the middle of the map an island's terrain starts to fall away into the ocean,
+	// as a fraction of the distance to the edge of the map
+	shoreStart = 0.6
+)
+
+// Get a generator for terrain made of a single layer of noise stretched across the whole map
+func (s *Simulation) classicTerrain() func(x, z int) float32 {
+	pnoise := perlin.NewPerlin(1, 0.1, 2, s.State.Rand.Int63())
+
+	return func(x, z int) float32 {
+		return float32(math.Abs(pnoise.Noise2D(float64(x), float64(z))))
+	}
+}
+
+// Get a generator for an island rising out of the ocean in the middle of the map.
+// Layers of noise are added together for the hills and valleys, and fall away towards the edges of the map
+// at a distance that wanders around the island so that its coastline isn't a perfect oval.
+func (s *Simulation) islandTerrain() func(x, z int) float32 {
+	octaves := s.Cfg.Terrain.Octaves
+	roughness := float64(s.Cfg.Terrain.Roughness)
+	coastline := float64(s.Cfg.Terrain.Coastline)
+
+	// Each layer of noise is twice as fine as the one before it, and adds roughness times as much
+	terrain := perlin.NewPerlin(1/roughness, 2, int32(octaves), s.State.Rand.Int63())
+	coast := perlin.NewPerlin(2, 2, 2, s.State.Rand.Int63())
+
+	// The most that all the layers of noise can add up to
+	var amplitude float64
+	for i := 0; i < octaves; i++ {
+		amplitude += math.Pow(roughness, float64(i))
+	}
+
+	cx := float64(s.Cfg.Simulation.Width-1) / 2
+	cz := float64(s.Cfg.Simulation.Depth-1) / 2
+
+	return func(x, z int) float32 {
+		// How far the tile is from the centre of the map, from 0 in the middle to 1 halfway along each edge
+		distance := math.Hypot((float64(x)-cx)/cx, (float64(z)-cz)/cz)
+
+		// Pull the coast in towards the middle by a different amount around the island
+		distance += coastline * (1 - shoreStart) * (coast.Noise2D(float64(x)/coastScale, float64(z)/coastScale) + 1) / 2
+
+		// The terrain falls away smoothly from the start of the shore to nothing at the edge of the map
+		t := math.Min(1, math.Max(0, (distance-shoreStart)/(1-shoreStart)))
+		falloff := 1 - t*t*(3-2*t)
+		height := (terrain.Noise2D(float64(x)/islandScale, float64(z)/islandScale)/amplitude + 1) / 2
+
+		return float32(height * falloff)
+	}
+}
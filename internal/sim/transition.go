@@ -0,0 +1,70 @@
+package sim
+
+import (
+	"cbeimers113/strands/internal/entity"
+)
+
+// The number of seconds in a day of simulated time
+const day float32 = 24 * 60 * 60
+
+// Transition is a rule for when a tile turns from one type into another.
+// A tile turns once it has met every condition of the rule for long enough without a break.
+type Transition struct {
+	From       string     // The name of the tile type the rule applies to
+	To         string     // The name of the tile type the tile turns into
+	Saturation [2]float32 // The range that the tile's soil saturation has to stay within, from 0 for dry to 1 for saturated
+	Plants     int        // The fewest plants that have to be growing on the tile
+	Duration   float32    // How many seconds of simulated time the conditions have to hold for
+}
+
+// The rules for tiles turning from one type into another, rules for tile types that don't exist are skipped
+var Transitions = []Transition{
+	// Dirt that stays moist with plants growing on it grows over with grass
+	{From: "dirt", To: "grass", Saturation: [2]float32{0.4, 1}, Plants: 1, Duration: 2 * day},
+
+	// Grass dies back to dirt in a drought
+	{From: "grass", To: "dirt", Saturation: [2]float32{0, 0.1}, Duration: 2 * day},
+
+	// Sand turns to mud once it's soaked, and back again once it dries out
+	{From: "sand", To: "mud", Saturation: [2]float32{0.9, 1}, Duration: day / 4},
+	{From: "mud", To: "sand", Saturation: [2]float32{0, 0.3}, Duration: day},
+}
+
+// Check whether a tile meets the conditions of a transition
+func (r Transition) holds(tile *entity.Tile) bool {
+	saturation := tile.Saturation()
+
+	return tile.Type.Name == r.From &&
+		saturation >= r.Saturation[0] && saturation <= r.Saturation[1] &&
+		len(tile.Plants) >= r.Plants
+}
+
+// Turn the tiles that have met the conditions of a transition for long enough into their new type,
+// over a number of seconds of simulated time. A tile that stops meeting the conditions starts over.
+func (s *Simulation) transformTiles(seconds float32) {
+	for _, tile := range s.GetTiles() {
+		var rule *Transition
+		for i := range Transitions {
+			if Transitions[i].holds(tile) {
+				rule = &Transitions[i]
+				break
+			}
+		}
+
+		if rule == nil {
+			tile.Transition = 0
+			continue
+		}
+
+		tType, ok := entity.TileTypeNamed(rule.To)
+		if !ok {
+			continue
+		}
+
+		tile.Transition += seconds
+		if tile.Transition >= rule.Duration {
+			tile.Type = tType
+			tile.Transition = 0
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package sim
+
+import (
+	"cbeimers113/strands/internal/chem"
+	"cbeimers113/strands/internal/entity"
+	"cbeimers113/strands/internal/state"
+)
+
+var (
+	// How quickly water evaporates off a tile, in metres per second. Multiplied by how many more litres
+	// per cubic metre the air above the tile could hold, this gives litres per square metre per second.
+	EvaporationVelocity float32 = 0.005
+
+	// The fraction of a cell's excess water vapour that condenses onto the tile below per second
+	CondensationRate float32 = 0.01
+
+	// How quickly water evaporates out of saturated soil, as a fraction of how quickly standing water evaporates.
+	// Drier soil evaporates proportionally slower.
+	SoilEvaporation float32 = 0.2
+)
+
+// Get the atmosphere cell that the surface of a tile sits in
+func (s *Simulation) cellAbove(tile *entity.Tile) *state.Cell {
+	return s.atmosphere.Cell(tile.MapX, s.surfaceY(tile), tile.MapZ)
+}
+
+// Exchange water between every tile and the cell above it over a number of seconds of simulated time.
+// Cells are one cubic metre, so their vapour in litres is also their humidity in litres per cubic metre.
+func (s *Simulation) exchangeWater(seconds float32) {
+	// Tally the water that changes pools over the whole map before applying it to the totals,
+	// since the many tiny per-tile amounts would be lost to rounding against the large totals
+	var evaporated, dried float64
+
+	for x := 0; x < s.Cfg.Simulation.Width; x++ {
+		for z := 0; z < s.Cfg.Simulation.Depth; z++ {
+			tile := s.tilemap[x][z]
+			vapour := s.cellAbove(tile).Quantities[chem.Water]
+
+			// Standing water evaporates until the air above it is saturated at the water's temperature
+			deficit := chem.SaturationHumidity(tile.Temperature.Value) - vapour.Value
+			if deficit > 0 && tile.WaterLevel.Value > 0 {
+				amount := min(deficit*EvaporationVelocity*seconds, deficit, tile.WaterLevel.Value)
+				evaporated += float64(moveWater(tile, vapour, amount))
+				deficit -= amount
+			}
+
+			// Once the standing water is gone, the soil dries out into whatever room is left in the air
+			if deficit > 0 && tile.WaterLevel.Value <= 0 && tile.SoilMoisture.Value > 0 {
+				amount := min(deficit*EvaporationVelocity*SoilEvaporation*tile.Saturation()*seconds, deficit, tile.SoilMoisture.Value)
+				tile.SoilMoisture.Value -= amount
+				vapour.Value += amount
+				dried += float64(amount)
+			}
+
+			// Any vapour beyond what the air can hold at its own temperature condenses back onto the tile
+			excess := vapour.Value - chem.SaturationHumidity(s.cellAbove(tile).Temperature)
+			if excess > 0 {
+				amount := excess * min(1, CondensationRate*seconds)
+				evaporated += float64(moveWater(tile, vapour, -amount))
+			}
+		}
+	}
+
+	s.State.Quantities[chem.Water].Value -= float32(evaporated)
+	s.State.Quantities[chem.SoilWater].Value -= float32(dried)
+	s.State.Quantities[chem.WaterVapour].Value += float32(evaporated + dried)
+}
+
+// Move litres of water from a tile into the vapour of a cell, a negative amount moves it from the vapour onto the tile.
+// Returns the amount that was moved.
+func moveWater(tile *entity.Tile, vapour *chem.Quantity, amount float32) float32 {
+	tile.AddWater(-amount)
+	vapour.Value += amount
+
+	return amount
+}
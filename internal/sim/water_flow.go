@@ -0,0 +1,52 @@
+package sim
+
+// Spread the water on each tile to its lower neighbours. Every tile plans its spread into a buffer before any water moves,
+// then the buffer is applied all at once, so the flow is the same whichever order the tiles are visited in.
+func (s *Simulation) flowWater() {
+	width := s.Cfg.Simulation.Width
+	depth := s.Cfg.Simulation.Depth
+
+	seconds := s.State.Clock.TickSeconds()
+	s.allocateFlow()
+
+	for x := 0; x < width; x++ {
+		for z := 0; z < depth; z++ {
+			tile := s.tilemap[x][z]
+
+			for i, amount := range tile.PlanWaterSpread(seconds) {
+				if amount <= 0 {
+					continue
+				}
+
+				neighbour := tile.Neighbours[i]
+				s.outflow[x+z*width][i] += amount
+				s.flow[x+z*width] -= amount
+				s.flow[neighbour.MapX+neighbour.MapZ*width] += amount
+			}
+		}
+	}
+
+	for x := 0; x < width; x++ {
+		for z := 0; z < depth; z++ {
+			tile := s.tilemap[x][z]
+
+			// The tiles that spread their water this tick wait for their next turn, checked before the flow changes their water
+			if tile.SpreadsWater(seconds) {
+				tile.WaterTick = 0
+			}
+
+			tile.AddWater(s.flow[x+z*width])
+			s.flow[x+z*width] = 0
+		}
+	}
+}
+
+// Make sure the buffers that water flow is planned in fit the map
+func (s *Simulation) allocateFlow() {
+	size := s.Cfg.Simulation.Width * s.Cfg.Simulation.Depth
+
+	if len(s.flow) != size {
+		s.flow = make([]float32, size)
+		s.outflow = make([][6]float32, size)
+	}
+}
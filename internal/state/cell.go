@@ -1,9 +1,14 @@
 package state
 
-import "cbeimers113/strands/internal/chem"
+import (
+	"github.com/g3n/engine/math32"
+
+	"cbeimers113/strands/internal/chem"
+)
 
 // Cell represents a single 3D section of chemical quantities and temperature in the atmosphere
 type Cell struct {
 	Quantities  map[chem.ElementType]*chem.Quantity `json:"quantities"`
 	Temperature float32                             `json:"temperature"`
+	Wind        math32.Vector3                      `json:"wind"` // The velocity of the air in the cell in m/s
 }
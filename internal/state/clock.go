@@ -3,10 +3,31 @@ package state
 import (
 	"fmt"
 
+	"github.com/g3n/engine/math32"
+
 	"cbeimers113/strands/internal/config"
 )
 
-// Represents the local time of the simulation world
+// Season is a quarter of the year
+type Season string
+
+const (
+	Spring Season = "Spring"
+	Summer Season = "Summer"
+	Autumn Season = "Autumn"
+	Winter Season = "Winter"
+)
+
+// The seasons in the order they come in the northern hemisphere, starting from the spring equinox
+var seasons = []Season{Spring, Summer, Autumn, Winter}
+
+// The tilt of the world's axis in radians, which is how far north or south of the equator the sun gets over the year
+const axialTilt float32 = 23.44 * math32.Pi / 180
+
+// The number of seconds in a day
+const daySeconds = 60 * 60 * 24
+
+// Represents the local time of the simulation world, which moves forward by the same amount of time every tick
 type Clock struct {
 	*config.Config `json:"-"`
 
@@ -15,15 +36,18 @@ type Clock struct {
 	Day    int `json:"day"`
 
 	TwelveHr bool    `json:"twelve_hr"`
-	Timer    float32 `json:"timer"`
+	Seconds  int     `json:"seconds"`         // How many seconds of simulated time have passed since midnight
+	Ticks    int     `json:"ticks"`           // How many ticks have passed since the simulation started
+	Timer    float32 `json:"timer,omitempty"` // How many ms of real time had passed since midnight, only set in saves from before ticks had a fixed length
 }
 
-// Create a new clock with the specified time given the in-game day length
+// The length of a day in minutes of real time before ticks had a fixed length, if the config doesn't say otherwise
+const legacyDayLength = 5
+
+// Create a new clock with the specified time
 func NewClock(cfg *config.Config, hour, minute int, twelveHr bool) *Clock {
 	c := &Clock{
 		Config:   cfg,
-		Minute:   minute,
-		Hour:     hour,
 		TwelveHr: twelveHr,
 	}
 
@@ -32,31 +56,125 @@ func NewClock(cfg *config.Config, hour, minute int, twelveHr bool) *Clock {
 	return c
 }
 
-// SetTime sets the internal timer to the given hour and minute for a given day length
+// SetTime sets the time of day to the given hour and minute
 func (c *Clock) SetTime(hour, minute int) {
-	ms := float32(60 * 1000 * (minute + hour*60))                                  // Number of ms between midnight and the selected time
-	c.Timer = float32(c.Simulation.DayLength*60*1000) * ms / (1000 * 60 * 60 * 24) // Scale the number of ms to the game day length
+	c.Seconds = 60 * (minute + hour*60)
+	c.tell()
+}
+
+// Convert the timer of a save from before ticks had a fixed length into the seconds since midnight.
+// The timer counted ms of real time over a day that lasted the configured day length in minutes.
+func (c *Clock) migrateTimer() {
+	if c.Timer <= 0 {
+		return
+	}
+
+	dayLength := c.Simulation.DayLength
+	if dayLength <= 0 {
+		dayLength = legacyDayLength
+	}
+
+	progress := c.Timer / float32(dayLength*60*1000)
+	c.Seconds = int(progress*daySeconds) % daySeconds
+	c.Timer = 0
+}
+
+// Work out the hour and minute from the number of seconds since midnight
+func (c *Clock) tell() {
+	c.Hour = c.Seconds / 3600
+	c.Minute = c.Seconds / 60 % 60
 }
 
 // Progress returns percentage of progress through the day
-func (c Clock) Progress(int) float32 {
-	return c.Timer / float32(c.Simulation.DayLength*60*1000)
+func (c Clock) Progress() float32 {
+	return float32(c.Seconds) / daySeconds
+}
+
+// DayOfYear returns how many whole days have passed since the start of the year
+func (c Clock) DayOfYear() int {
+	if c.Calendar.YearLength < 1 {
+		return c.Day
+	}
+
+	return c.Day % c.Calendar.YearLength
 }
 
-// Update adds ms of real time into the world's time
-func (c *Clock) Update(ms float32) {
-	c.Timer += ms
+// Year returns how many whole years have passed since the simulation started
+func (c Clock) Year() int {
+	if c.Calendar.YearLength < 1 {
+		return 0
+	}
+
+	return c.Day / c.Calendar.YearLength
+}
 
-	// 1440 minutes in a day, so set the minutes count equal to n% of 1440, where n is the progress through the day
-	numMins := int(c.Progress(c.Simulation.DayLength) * 1440)
-	c.Minute = numMins % 60
-	c.Hour = int(numMins / 60)
+// YearProgress returns percentage of progress through the year, which starts at the spring equinox
+func (c Clock) YearProgress() float32 {
+	if c.Calendar.YearLength < 1 {
+		return 0
+	}
 
-	if c.Hour >= 24 {
-		c.Timer = 0
-		c.Hour = 0
+	return (float32(c.DayOfYear()) + c.Progress()) / float32(c.Calendar.YearLength)
+}
+
+// Season returns the season at the map's latitude, the seasons in the southern hemisphere are opposite to the northern ones
+func (c Clock) Season() Season {
+	i := int(4*c.YearProgress()) % 4
+	if c.Calendar.Latitude < 0 {
+		i = (i + 2) % 4
+	}
+
+	return seasons[i]
+}
+
+// Get the angle of the sun north of the equator in radians, which swings between the tropics over the year
+func (c Clock) declination() float32 {
+	return axialTilt * math32.Sin(2*math32.Pi*c.YearProgress())
+}
+
+// SunDirection returns a unit vector pointing from the map towards the sun, with x towards the east, y up and z towards the south
+func (c Clock) SunDirection() math32.Vector3 {
+	latitude := c.Calendar.Latitude * math32.Pi / 180
+	declination := c.declination()
+	hourAngle := 2 * math32.Pi * (c.Progress() - 0.5)
+
+	east := -math32.Cos(declination) * math32.Sin(hourAngle)
+	north := math32.Cos(latitude)*math32.Sin(declination) - math32.Sin(latitude)*math32.Cos(declination)*math32.Cos(hourAngle)
+	up := math32.Sin(latitude)*math32.Sin(declination) + math32.Cos(latitude)*math32.Cos(declination)*math32.Cos(hourAngle)
+
+	return math32.Vector3{X: east, Y: up, Z: -north}
+}
+
+// SunElevation returns the sine of the sun's angle above the horizon. At the equator on an equinox it goes from -1 at midnight
+// through 0 at 6 am and 6 pm to 1 at noon, further from the equator the sun stays lower and the days are longer in summer.
+func (c Clock) SunElevation() float32 {
+	return c.SunDirection().Y
+}
+
+// Daylight returns how many hours the sun spends above the horizon today
+func (c Clock) Daylight() float32 {
+	latitude := c.Calendar.Latitude * math32.Pi / 180
+	x := -math32.Tan(latitude) * math32.Tan(c.declination())
+
+	return 24 * math32.Acos(math32.Max(-1, math32.Min(1, x))) / math32.Pi
+}
+
+// TickSeconds returns how many seconds of simulated time pass in each tick
+func (c Clock) TickSeconds() float32 {
+	return float32(c.Simulation.TickLength)
+}
+
+// Update moves the world's time forward by one tick
+func (c *Clock) Update() {
+	c.Ticks++
+	c.Seconds += c.Simulation.TickLength
+
+	for c.Seconds >= daySeconds {
+		c.Seconds -= daySeconds
 		c.Day++
 	}
+
+	c.tell()
 }
 
 // Get a string representation of the current time
@@ -90,5 +208,9 @@ func (c Clock) String() string {
 		}
 	}
 
-	return fmt.Sprintf("%s:%s %s, Day %d", h, m, amPm, c.Day)
+	if c.Calendar.YearLength < 1 {
+		return fmt.Sprintf("%s:%s %s, Day %d", h, m, amPm, c.Day)
+	}
+
+	return fmt.Sprintf("%s:%s %s, %s, Day %d of Year %d", h, m, amPm, c.Season(), c.DayOfYear()+1, c.Year()+1)
 }
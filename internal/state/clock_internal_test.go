@@ -0,0 +1,173 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"cbeimers113/strands/internal/config"
+)
+
+func Test_seasons(t *testing.T) {
+	tests := []struct {
+		name     string
+		day      int
+		latitude float32
+		season   Season
+		daylight float32 // Roughly how many hours the sun is up for
+	}{
+		{
+			name:     "Happy path - the year starts at the spring equinox",
+			day:      0,
+			latitude: 40,
+			season:   Spring,
+			daylight: 12,
+		},
+		{
+			name:     "Happy path - summer days are long",
+			day:      10,
+			latitude: 40,
+			season:   Summer,
+			daylight: 14.9,
+		},
+		{
+			name:     "Happy path - winter days are short",
+			day:      30,
+			latitude: 40,
+			season:   Winter,
+			daylight: 9.1,
+		},
+		{
+			name:     "Happy path - the seasons are opposite in the southern hemisphere",
+			day:      10,
+			latitude: -40,
+			season:   Winter,
+			daylight: 9.1,
+		},
+		{
+			name:     "Sad path - days at the equator don't change length",
+			day:      10,
+			latitude: 0,
+			season:   Summer,
+			daylight: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Simulation.TickLength = 12
+			cfg.Calendar.YearLength = 40
+			cfg.Calendar.Latitude = tt.latitude
+
+			// Noon on the given day
+			c := NewClock(cfg, 12, 0, false)
+			c.Day = tt.day
+
+			assert.Equal(t, tt.season, c.Season())
+			assert.InDelta(t, tt.daylight, c.Daylight(), 0.25)
+			assert.Equal(t, tt.day, c.DayOfYear())
+		})
+	}
+}
+
+func Test_Update(t *testing.T) {
+	tests := []struct {
+		name       string
+		tickLength int
+		ticks      int
+		day        int
+		hour       int
+		minute     int
+	}{
+		{
+			name:       "Happy path - a day lasts the same number of ticks every time",
+			tickLength: 12,
+			ticks:      7200,
+			day:        1,
+			hour:       0,
+			minute:     0,
+		},
+		{
+			name:       "Happy path - longer ticks make the day go by in fewer of them",
+			tickLength: 60,
+			ticks:      1530,
+			day:        1,
+			hour:       1,
+			minute:     30,
+		},
+		{
+			name:       "Sad path - a tick that doesn't divide the day carries the remainder into the next one",
+			tickLength: 7,
+			ticks:      12343,
+			day:        1,
+			hour:       0,
+			minute:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Simulation.TickLength = tt.tickLength
+
+			c := NewClock(cfg, 0, 0, false)
+			for i := 0; i < tt.ticks; i++ {
+				c.Update()
+			}
+
+			assert.Equal(t, tt.day, c.Day)
+			assert.Equal(t, tt.hour, c.Hour)
+			assert.Equal(t, tt.minute, c.Minute)
+			assert.Equal(t, tt.ticks, c.Ticks)
+		})
+	}
+}
+
+func Test_migrateTimer(t *testing.T) {
+	tests := []struct {
+		name      string
+		timer     float32
+		dayLength int
+		hour      int
+		minute    int
+	}{
+		{
+			name:      "Happy path - noon on a day of the configured length",
+			timer:     5 * 60 * 1000,
+			dayLength: 10,
+			hour:      12,
+			minute:    0,
+		},
+		{
+			name:   "Happy path - a config without a day length uses the old default",
+			timer:  5 * 60 * 1000 / 4,
+			hour:   6,
+			minute: 0,
+		},
+		{
+			name:      "Sad path - a save from after ticks had a fixed length keeps its time",
+			timer:     0,
+			dayLength: 10,
+			hour:      18,
+			minute:    30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Simulation.TickLength = 12
+			cfg.Simulation.DayLength = tt.dayLength
+
+			c := NewClock(cfg, 18, 30, false)
+			c.Timer = tt.timer
+			c.migrateTimer()
+			c.tell()
+
+			assert.Equal(t, tt.hour, c.Hour)
+			assert.Equal(t, tt.minute, c.Minute)
+			assert.Zero(t, c.Timer)
+		})
+	}
+}
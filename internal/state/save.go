@@ -9,7 +9,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/g3n/engine/math32"
 	"github.com/valyala/gozstd"
 
 	"cbeimers113/strands/internal/config"
@@ -33,10 +32,11 @@ type CamData struct {
 
 // Save is for serializing game save data
 type Save struct {
-	Seed  int64          `json:"seed"`
-	Clock *Clock         `json:"clock"`
-	Cells []*Cell        `json:"atmosphere"`
-	Tiles []*entity.Tile `json:"tiles"` //plants are embedded in the tiles they're on
+	Seed   int64          `json:"seed"`
+	Clock  *Clock         `json:"clock"`
+	Births map[string]int `json:"births"`
+	Cells  []*Cell        `json:"atmosphere"`
+	Tiles  []*entity.Tile `json:"tiles"` //plants are embedded in the tiles they're on
 	// creatures []*entity.Creature `json:"creatures"`
 	Camera CamData `json:"camera"`
 }
@@ -69,24 +69,24 @@ func LoadSave(cfg *config.Config, filename string) (*State, []*Cell, []*entity.T
 	state = New(cfg, save.Seed)
 	state.Clock = save.Clock
 	state.Clock.Config = cfg
+	state.Clock.migrateTimer()
+	state.Clock.tell()
+
+	if save.Births != nil {
+		state.Births = save.Births
+	}
 
 	return state, save.Cells, save.Tiles, save.Camera, nil
 }
 
-func StoreSave(filename string, state *State, cells []*Cell, tiles []*entity.Tile, pos, rot math32.Vector3) error {
+func StoreSave(filename string, state *State, cells []*Cell, tiles []*entity.Tile, camera CamData) error {
 	save := Save{
-		Seed:  state.Seed,
-		Clock: state.Clock,
-		Cells: cells,
-		Tiles: tiles,
-		Camera: CamData{
-			PosX: pos.X,
-			PosY: pos.Y,
-			PosZ: pos.Z,
-
-			RotX: rot.X,
-			RotY: rot.Y,
-		},
+		Seed:   state.Seed,
+		Clock:  state.Clock,
+		Births: state.Births,
+		Cells:  cells,
+		Tiles:  tiles,
+		Camera: camera,
 	}
 
 	data, err := json.MarshalIndent(save, "", "	")
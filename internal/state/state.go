@@ -2,10 +2,9 @@ package state
 
 import (
 	"math/rand"
+	"sort"
 	"strconv"
 
-	"github.com/g3n/engine/core"
-
 	"cbeimers113/strands/internal/chem"
 	"cbeimers113/strands/internal/config"
 	"cbeimers113/strands/internal/entity"
@@ -27,6 +26,7 @@ type State struct {
 	LookingAt  entity.Entity                       // What the camera/player is looking at
 	Entities   map[int]entity.Entity               // List of entities in the game world
 	Quantities map[chem.ElementType]*chem.Quantity // Map of quantities for tracking various substances in the simulation
+	Births     map[string]int                      // How many plants of each species have sprouted from seed
 }
 
 func New(cfg *config.Config, seed int64) *State {
@@ -38,6 +38,7 @@ func New(cfg *config.Config, seed int64) *State {
 		Clock:      NewClock(cfg, 9, 00, true),
 		Entities:   make(map[int]entity.Entity),
 		Quantities: make(map[string]*chem.Quantity),
+		Births:     make(map[string]int),
 	}
 }
 
@@ -116,11 +117,33 @@ func (s State) ShowChems() bool {
 	return s.showChems
 }
 
-// Get the entity associated with a node, return nil if there isn't one
-func (s State) EntityOf(node *core.Node) entity.Entity {
-	if i, err := strconv.Atoi(node.Name()); err == nil {
+// Get the entity associated with a game object's name, return nil if there isn't one
+func (s State) EntityOf(name string) entity.Entity {
+	if i, err := strconv.Atoi(name); err == nil {
 		return s.Entities[i]
 	}
 
 	return nil
 }
+
+// Get the names of every tracked quantity in alphabetical order, so that they're always listed the same way
+func (s State) QuantityNames() []chem.ElementType {
+	names := make([]chem.ElementType, 0, len(s.Quantities))
+	for name := range s.Quantities {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Get the names of every species that has had a plant sprout from seed in alphabetical order
+func (s State) SpeciesNames() []string {
+	names := make([]string, 0, len(s.Births))
+	for name := range s.Births {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,123 @@
+package world
+
+import (
+	"fmt"
+
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+
+	"cbeimers113/strands/internal/entity"
+	"cbeimers113/strands/internal/graphics"
+)
+
+// The game objects that represent a plant
+type plantView struct {
+	mesh    *graphic.Mesh   // The stalk
+	leaves  []*graphic.Mesh // The leaves attached to the stalk
+	parent  *graphic.Mesh   // The tile mesh the plant is attached to
+	burning bool            // Whether the plant is drawn on fire
+}
+
+// The glow of a burning plant
+var fireGlow = math32.NewColorHex(0xff5a00)
+
+// Create a new leaf
+func createLeafMesh() (mesh *graphic.Mesh) {
+	geom := graphics.NewLeafMesh(2, 6, 2, 2)
+	mat := material.NewStandard(math32.NewColorHex(0x101010))
+	mesh = graphic.NewMesh(geom, mat)
+
+	if tex, err := graphics.Texture(graphics.TexGrass); err == nil {
+		mat.AddTexture(tex)
+	} else {
+		fmt.Println(err)
+	}
+
+	return
+}
+
+// Create the stalk mesh for a plant
+func createPlantMesh(plant *entity.Plant) (mesh *graphic.Mesh) {
+	geom := geometry.NewCylinder(float64(plant.Radius), float64(plant.Height), 8, 8, true, true)
+	mat := material.NewStandard(math32.NewColorHex(uint(plant.Colour) / 10))
+	mesh = graphic.NewMesh(geom, mat)
+	mesh.SetScale(0.1, 0.1, 0.1)
+
+	if tex, err := graphics.Texture(graphics.TexStalk); err == nil {
+		mat.AddTexture(tex)
+	} else {
+		fmt.Println(err)
+	}
+
+	return
+}
+
+// Create the game objects for a plant, or bring the existing ones up to date with the simulation
+func (w *World) syncPlant(plant *entity.Plant, tile *tileView) {
+	view, ok := w.plants[plant]
+
+	if !ok {
+		view = &plantView{
+			mesh:   createPlantMesh(plant),
+			parent: tile.mesh,
+		}
+
+		w.register(plant, view.mesh)
+		view.mesh.SetRotation(plant.RotX, plant.RotY, 0)
+		tile.mesh.Add(view.mesh)
+		w.plants[plant] = view
+	}
+
+	// Make sure each leaf exists
+	for len(view.leaves) < plant.NumLeaves {
+		leaf := createLeafMesh()
+		leaf.SetName(view.mesh.Name())
+		leaf.SetScale(0.1, 0.1, 0.1)
+		leaf.SetRotation(w.rand.Float32()*math32.Pi/12, w.rand.Float32()*2*math32.Pi, w.rand.Float32()*math32.Pi/12)
+		view.mesh.Add(leaf)
+		view.leaves = append(view.leaves, leaf)
+	}
+
+	// Grow the stalk
+	scale := view.mesh.Scale()
+	scale.Y = plant.Growth()
+	view.mesh.SetScale(scale.X, scale.Y, scale.Z)
+	view.mesh.SetPosition(plant.X, 0.5+scale.Y/2, plant.Z)
+
+	if view.burning != plant.Burning {
+		setAlight(view, plant.Burning)
+	}
+
+	highlight(view.mesh, w.State.LookingAt == plant)
+}
+
+// Make a plant glow while it's burning, or stop it glowing once the fire is out
+func setAlight(view *plantView, burning bool) {
+	glow := &math32.Color{}
+	if burning {
+		glow = fireGlow
+	}
+
+	for _, mesh := range append([]*graphic.Mesh{view.mesh}, view.leaves...) {
+		if ms, ok := mesh.GetMaterial(0).(*material.Standard); ok {
+			ms.SetEmissiveColor(glow)
+		}
+	}
+
+	view.burning = burning
+}
+
+// Remove the game objects of a plant that no longer exists in the simulation
+func (w *World) removePlant(plant *entity.Plant, view *plantView) {
+	w.unregister(view.mesh)
+	view.parent.Remove(view.mesh)
+	view.mesh.DisposeChildren(true)
+	view.mesh.Dispose()
+	delete(w.plants, plant)
+
+	if w.State.LookingAt == plant {
+		w.State.LookingAt = nil
+	}
+}
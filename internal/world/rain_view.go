@@ -0,0 +1,59 @@
+package world
+
+import (
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+
+	"cbeimers113/strands/internal/entity"
+	"cbeimers113/strands/internal/graphics"
+	"cbeimers113/strands/internal/sim"
+)
+
+const (
+	rainHeight  float32 = 6    // How far above a tile rain streaks are drawn, in metres
+	rainBundles         = 4    // How many bundles of streaks make up the heaviest rain
+	rainStreaks         = 8    // How many streaks are in each bundle
+	rainFall    float32 = 0.25 // How far the streaks fall each time the world is redrawn, in metres
+)
+
+// Create the streaks of rain drawn above a tile, hidden until it rains
+func (w *World) createRain(view *tileView) {
+	view.rain = core.NewNode()
+	view.rain.SetVisible(false)
+
+	for i := 0; i < rainBundles; i++ {
+		bundle := graphic.NewLines(graphics.NewRainGeometry(rainStreaks, rainHeight, w.rand), material.NewBasic())
+		bundle.SetName(view.mesh.Name())
+		bundle.SetVisible(false)
+		view.rain.Add(bundle)
+	}
+
+	view.mesh.Add(view.rain)
+}
+
+// Show rain falling on a tile, with more streaks the heavier it rains
+func (w *World) updateRain(tile *entity.Tile, view *tileView) {
+	if tile.Rain <= 0 {
+		if view.rain != nil {
+			view.rain.SetVisible(false)
+		}
+
+		return
+	}
+
+	if view.rain == nil {
+		w.createRain(view)
+	}
+
+	// Storm intensity rain shows half of the bundles, and twice that shows all of them
+	shown := int(float32(rainBundles)*min(1, tile.Rain/(2*sim.StormIntensity))) + 1
+	for i, bundle := range view.rain.Children() {
+		bundle.GetNode().SetVisible(i < shown)
+	}
+
+	// Move the streaks down, jumping them back up by a metre so that they stay above the tile
+	fall := w.rainFall - float32(int(w.rainFall))
+	view.rain.SetPositionY(entity.TileHeight + 1 - fall)
+	view.rain.SetVisible(true)
+}
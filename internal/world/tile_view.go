@@ -0,0 +1,154 @@
+package world
+
+import (
+	"fmt"
+
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+
+	"cbeimers113/strands/internal/chem"
+	"cbeimers113/strands/internal/entity"
+	"cbeimers113/strands/internal/graphics"
+)
+
+// How many times deeper snow lies than the water it melts into
+const snowLoft float32 = 10
+
+// The game objects that represent a tile
+type tileView struct {
+	mesh      *graphic.Mesh // The tile itself
+	water     *graphic.Mesh // The water that can exist on top of the tile
+	frozen    *graphic.Mesh // The ice and snow that can lie on top of the tile's water
+	rain      *core.Node    // The rain falling on the tile, only created once it first rains there
+	texture   string        // The name of the texture currently applied to the tile
+	frozenTex string        // The name of the texture currently applied to the ice and snow
+}
+
+// Create a base tile mesh with a given texture
+func createTileMesh(texture string) (tileMesh *graphic.Mesh) {
+	geom := graphics.NewHexMesh()
+	mat := material.NewStandard(math32.NewColorHex(0x111111))
+	mat.SetTransparent(texture == graphics.TexWater)
+	tileMesh = graphic.NewMesh(geom, mat)
+
+	if tex, err := graphics.Texture(texture); err == nil {
+		mat.AddTexture(tex)
+	} else {
+		fmt.Println(err)
+	}
+
+	return
+}
+
+// Create the game objects for a tile, or bring the existing ones up to date with the simulation
+func (w *World) syncTile(tile *entity.Tile) {
+	view, ok := w.tiles[tile]
+
+	if !ok {
+		view = &tileView{
+			mesh:    createTileMesh(tile.Type.Texture),
+			texture: tile.Type.Texture,
+		}
+
+		w.register(tile, view.mesh)
+		view.mesh.SetRotationY(math32.Pi / 2)
+		view.mesh.GetMaterial(0).GetMaterial().SetLineWidth(8)
+
+		view.water = createTileMesh(graphics.TexWater)
+		view.water.SetName(view.mesh.Name())
+		view.mesh.Add(view.water)
+
+		view.frozen = createTileMesh(graphics.TexIce)
+		view.frozen.SetName(view.mesh.Name())
+		view.frozenTex = graphics.TexIce
+		view.mesh.Add(view.frozen)
+
+		w.Scene.Add(view.mesh)
+		w.tiles[tile] = view
+	}
+
+	view.mesh.SetPosition(tile.WorldX(), tile.WorldY, tile.WorldZ())
+
+	// Swap the texture if the tile has changed type
+	if view.texture != tile.Type.Texture {
+		if err := retexture(view.mesh, tile.Type.Texture); err == nil {
+			view.texture = tile.Type.Texture
+		} else {
+			fmt.Printf("Couldn't get tile texture for %s: %s\n", tile.Type.Name, err)
+		}
+	}
+
+	w.updateWaterLevel(tile, view)
+	w.updateFrozen(tile, view)
+	w.updateRain(tile, view)
+	highlight(view.mesh, w.State.LookingAt == tile)
+
+	for _, plant := range tile.Plants {
+		w.syncPlant(plant, view)
+	}
+}
+
+// Update the tile's water mesh to match its water level
+func (w *World) updateWaterLevel(tile *entity.Tile, view *tileView) {
+	waterLevel := tile.WaterLevel.Value
+	water := view.water
+
+	water.SetScaleY(chem.LitresToCubicMetres(waterLevel))
+	water.SetPositionY(graphics.DimensionsOf(water).Y)
+	water.SetVisible(waterLevel > 0)
+
+	// Lower water texture opacity for low water level
+	if imat := water.GetMaterial(0); imat != nil {
+		if ms, ok := imat.(*material.Standard); ok {
+			ms.SetOpacity(math32.Min(50, waterLevel) / 60)
+		}
+	}
+}
+
+// Update the tile's ice and snow mesh to sit on top of its water, showing snow wherever any has settled
+func (w *World) updateFrozen(tile *entity.Tile, view *tileView) {
+	ice, snow := tile.Ice.Value, tile.Snow.Value
+	frozen := view.frozen
+
+	top := graphics.DimensionsOf(view.water).Y
+	frozen.SetScaleY(chem.LitresToCubicMetres(ice + snow*snowLoft))
+	frozen.SetPositionY(top + top*chem.LitresToCubicMetres(tile.WaterLevel.Value))
+	frozen.SetVisible(ice+snow > 0)
+
+	texture := graphics.TexIce
+	if snow > 0 {
+		texture = graphics.TexSnow
+	}
+
+	if view.frozenTex != texture {
+		if err := retexture(frozen, texture); err == nil {
+			view.frozenTex = texture
+		} else {
+			fmt.Printf("Couldn't get texture for %s: %s\n", texture, err)
+		}
+	}
+}
+
+// Replace the texture on a mesh, leaving the highlight alone
+func retexture(mesh *graphic.Mesh, texture string) error {
+	tex, err := graphics.Texture(texture)
+	if err != nil {
+		return err
+	}
+
+	mat := mesh.GetMaterial(0).GetMaterial()
+
+	// Remove any existing textures before adding the new one
+	for texName := range graphics.Textures {
+		if oTex, err := graphics.Texture(texName); err == nil && texName != graphics.TexHighlight {
+			if mat.HasTexture(oTex) {
+				mat.RemoveTexture(oTex)
+			}
+		}
+	}
+
+	mat.AddTexture(tex)
+	return nil
+}
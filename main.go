@@ -2,11 +2,17 @@ package main
 
 import (
 	_ "embed"
+	"flag"
 	"fmt"
+	"os"
 	"regexp"
+	"time"
 
+	"cbeimers113/strands/internal/chem"
 	"cbeimers113/strands/internal/config"
+	"cbeimers113/strands/internal/entity"
 	"cbeimers113/strands/internal/game"
+	"cbeimers113/strands/internal/sim"
 )
 
 //go:embed .version
@@ -22,6 +28,52 @@ func main() {
 		panic(err)
 	}
 
+	chem.DisplayUnits = cfg.DisplayUnits()
+
+	if err = entity.LoadTileTypes(); err != nil {
+		panic(err)
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "run":
+			run(cfg, os.Args[2:])
+			return
+		default:
+			fmt.Printf("Unknown command: %s\n", os.Args[1])
+			fmt.Println("Usage: strands [run [--headless] [--ticks N] [--load FILE] [--save FILE] [--seed SEED]]")
+			os.Exit(2)
+		}
+	}
+
+	startGame(cfg)
+}
+
+// Handle the run command, which either opens the game or advances a simulation without a window
+func run(cfg *config.Config, args []string) {
+	var opts sim.HeadlessOptions
+
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	headless := flags.Bool("headless", false, "run the simulation without a window or renderer")
+	flags.IntVar(&opts.Ticks, "ticks", 1000, "number of ticks to advance a headless simulation by")
+	flags.StringVar(&opts.LoadFile, "load", "", "save file to load instead of generating a new world")
+	flags.StringVar(&opts.SaveFile, "save", "", "save file to store the headless simulation in when it finishes")
+	flags.Int64Var(&opts.Seed, "seed", time.Now().UnixNano(), "seed for a newly generated world")
+	flags.Parse(args)
+
+	if !*headless {
+		startGame(cfg)
+		return
+	}
+
+	if err := sim.RunHeadless(cfg, opts); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// Open the game window and start the main loop
+func startGame(cfg *config.Config) {
 	g, err := game.New(cfg, Version)
 	if err != nil {
 		panic(err)